@@ -0,0 +1,113 @@
+package httpio
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/errors/v5"
+)
+
+// annotationKey is the context key for the response annotation
+type annotationKey struct{}
+
+// annotation records details of the response written by the Encoder for use by middleware
+type annotation struct {
+	// err holds the error written by Encoder.ClientMessage, after the error mappers have run
+	err error
+	// msgType holds the type of the ClientMessage that was written, if any
+	msgType *msgType
+	// messages holds the client messages that were written, after the Encoder's Redactor has run
+	messages []string
+	// spanRecorded is set once the error has been recorded on the active span
+	spanRecorded bool
+}
+
+// withAnnotation returns a context that collects the annotation of the response
+func withAnnotation(ctx context.Context) (context.Context, *annotation) {
+	if a, ok := ctx.Value(annotationKey{}).(*annotation); ok {
+		return ctx, a
+	}
+
+	a := &annotation{}
+
+	return context.WithValue(ctx, annotationKey{}, a), a
+}
+
+// annotate records the error written as a response, along with its redacted client messages,
+// when the context is collecting an annotation
+func annotate(ctx context.Context, err error, messages []string) {
+	a, ok := ctx.Value(annotationKey{}).(*annotation)
+	if !ok {
+		return
+	}
+
+	a.err = err
+	a.messages = messages
+	cerr := &ClientMessage{}
+	if errors.As(err, &cerr) {
+		a.msgType = &cerr.msgType
+	} else {
+		a.msgType = nil
+	}
+}
+
+// AccessLog returns middleware that writes one structured record to l for each request, or to
+// slog.Default() when l is nil. Records carry the method, path, route, status code, bytes written,
+// duration and trace ID, along with the message type and client messages of error responses
+// written by the Encoder. Client messages are recorded after the Encoder's Redactor has run.
+//
+// The trace ID is read from the cccteam/logger in the request context, falling back to the ID set by
+// RequestID, so AccessLog must be used after those middleware. Bytes are counted before compression when used inside Compress.
+func AccessLog(l *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ctx, a := withAnnotation(r.Context())
+			sw := &statusWriter{ResponseWriter: w}
+
+			defer func() {
+				log := l
+				if log == nil {
+					log = slog.Default()
+				}
+
+				status := sw.status
+				if status == 0 {
+					status = http.StatusOK
+				}
+
+				attrs := []slog.Attr{
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+					slog.Int("status", status),
+					slog.Int64("bytes", sw.bytes),
+					slog.Duration("duration", time.Since(start)),
+				}
+				if rctx := chi.RouteContext(ctx); rctx != nil && rctx.RoutePattern() != "" {
+					attrs = append(attrs, slog.String("route", rctx.RoutePattern()))
+				}
+				if traceID := requestTraceID(r.Context()); traceID != "" {
+					attrs = append(attrs, slog.String("traceId", traceID))
+				}
+				if a.msgType != nil {
+					attrs = append(attrs, slog.String("messageType", a.msgType.String()))
+				}
+				if len(a.messages) > 0 {
+					attrs = append(attrs, slog.Any("messages", a.messages))
+				}
+
+				level := slog.LevelInfo
+				if status >= http.StatusInternalServerError {
+					level = slog.LevelError
+				}
+
+				log.LogAttrs(ctx, level, "request", attrs...)
+			}()
+
+			next.ServeHTTP(sw, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package httpio
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/errors/v5"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAccessLog(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    map[string]any
+		absent  []string
+	}{
+		{
+			name: "ok",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_ = NewEncoder(w).Ok(map[string]string{"a": "b"})
+			},
+			want: map[string]any{
+				"level":  "INFO",
+				"msg":    "request",
+				"method": http.MethodGet,
+				"path":   "/items/12",
+				"route":  "/items/{id}",
+				"status": float64(http.StatusOK),
+				"bytes":  float64(len(`{"a":"b"}` + "\n")),
+			},
+			absent: []string{"messageType", "messages"},
+		},
+		{
+			name: "client message",
+			handler: Log(func(_ http.ResponseWriter, _ *http.Request) error {
+				return NewNotFoundMessageWithError(NewBadRequestMessage("inner"), "item not found")
+			}),
+			want: map[string]any{
+				"level":       "INFO",
+				"status":      float64(http.StatusNotFound),
+				"messageType": "notFound",
+				"messages":    []any{"item not found", "inner"},
+			},
+		},
+		{
+			name: "redacted client message",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_ = NewEncoder(w, WithRedactor(DefaultRedactor())).ClientMessage(r.Context(), NewNotFoundMessage("no user jane@example.com"))
+			},
+			want: map[string]any{
+				"status":   float64(http.StatusNotFound),
+				"messages": []any{"no user [REDACTED]"},
+			},
+		},
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_ = NewEncoder(w).ClientMessage(r.Context(), errors.New("database unavailable"))
+			},
+			want: map[string]any{
+				"level":  "ERROR",
+				"status": float64(http.StatusInternalServerError),
+			},
+			absent: []string{"messageType", "messages"},
+		},
+		{
+			name:    "nothing written",
+			handler: func(_ http.ResponseWriter, _ *http.Request) {},
+			want: map[string]any{
+				"status": float64(http.StatusOK),
+				"bytes":  float64(0),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			router := chi.NewRouter()
+			router.Use(AccessLog(slog.New(slog.NewJSONHandler(buf, nil))))
+			router.Get("/items/{id}", tt.handler)
+
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/12", http.NoBody))
+
+			var got map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v, log = %s", err, buf.String())
+			}
+			for k, v := range tt.want {
+				if diff := cmp.Diff(v, got[k]); diff != "" {
+					t.Errorf("AccessLog() attribute %s mismatch (-want +got):\n%s", k, diff)
+				}
+			}
+			for _, k := range tt.absent {
+				if v, ok := got[k]; ok {
+					t.Errorf("AccessLog() attribute %s = %v, want none", k, v)
+				}
+			}
+			if _, ok := got["duration"]; !ok {
+				t.Errorf("AccessLog() missing duration attribute")
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package httpio
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cccteam/ccc"
+	"github.com/gofrs/uuid"
+)
+
+// bindValues sets the exported fields of the struct pointed to by dst that carry the given struct tag.
+// lookup returns the values for a tag name, and values are converted using the same rules as Param.
+// Conversion failures are returned as a BadRequest (400) message.
+func bindValues(dst any, tag string, lookup func(name string) []string) (err error) {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			m, ok := rec.(paramErrMsg)
+			if !ok {
+				panic(rec)
+			}
+			err = NewBadRequestMessage(m.Msg())
+		}
+	}()
+
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		name, ok := field.Tag.Lookup(tag)
+		if !ok || !field.IsExported() {
+			continue
+		}
+		name, _, _ = strings.Cut(name, ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		values := lookup(name)
+		if len(values) == 0 {
+			continue
+		}
+
+		rv.Field(i).Set(bindValue(name, values, field.Type))
+	}
+
+	return nil
+}
+
+// bindValue converts values into a value of type t. Slices take every value, pointers are allocated,
+// and any other type takes the first value.
+func bindValue(name string, values []string, t reflect.Type) reflect.Value {
+	switch {
+	case isTextUnmarshaler(t):
+		return convertValue(name, values[0], t)
+	case t.Kind() == reflect.Slice:
+		s := reflect.MakeSlice(t, 0, len(values))
+		for _, v := range values {
+			s = reflect.Append(s, bindValue(name, []string{v}, t.Elem()))
+		}
+
+		return s
+	case t.Kind() == reflect.Pointer:
+		p := reflect.New(t.Elem())
+		p.Elem().Set(bindValue(name, values, t.Elem()))
+
+		return p
+	}
+
+	return convertValue(name, values[0], t)
+}
+
+// checkBindFields panics if a field of the struct t, or of the struct t points to, carries one of
+// the tags but has a type that bindValues cannot convert. It lets Handle fail when it is set up
+// rather than on every request.
+func checkBindFields(t reflect.Type, tags ...string) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		for _, tag := range tags {
+			name, ok := field.Tag.Lookup(tag)
+			if name, _, _ = strings.Cut(name, ","); !ok || name == "" || name == "-" {
+				continue
+			}
+			if !bindable(field.Type) {
+				panic(fmt.Sprintf("support for %s in field %s.%s has not been implemented", field.Type, t.Name(), field.Name))
+			}
+		}
+	}
+}
+
+// bindable reports whether bindValue can convert values into type t, following the rules of parseParam
+func bindable(t reflect.Type) bool {
+	if isTextUnmarshaler(t) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Pointer:
+		return bindable(t.Elem())
+	case reflect.String, reflect.Int, reflect.Int64, reflect.Float64, reflect.Bool:
+		return true
+	default:
+		return t == reflect.TypeFor[ccc.UUID]() || t.ConvertibleTo(reflect.TypeFor[uuid.UUID]())
+	}
+}
+
+// convertValue converts v into a value of type t using the same rules as Param
+func convertValue(name, v string, t reflect.Type) reflect.Value {
+	return reflect.ValueOf(parseParam(ParamType(name), v, reflect.Zero(t).Interface())).Convert(t)
+}
+
+func isTextUnmarshaler(t reflect.Type) bool {
+	return t.Implements(reflect.TypeFor[encoding.TextUnmarshaler]()) || reflect.PointerTo(t).Implements(reflect.TypeFor[encoding.TextUnmarshaler]())
+}
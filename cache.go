@@ -0,0 +1,127 @@
+package httpio
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CachePolicy describes the Cache-Control and Vary headers written with a response.
+// Durations are truncated to whole seconds and zero durations are omitted.
+type CachePolicy struct {
+	// Public marks the response as cacheable by any cache
+	Public bool
+	// Private marks the response as cacheable only by the client
+	Private bool
+	// NoCache requires caches to revalidate the response before using it
+	NoCache bool
+	// NoStore prevents the response from being stored by any cache
+	NoStore bool
+	// MustRevalidate prevents caches from serving the response once it is stale
+	MustRevalidate bool
+	// Immutable indicates the response will not change while it is fresh
+	Immutable bool
+	// MaxAge is how long the response is considered fresh
+	MaxAge time.Duration
+	// SMaxAge is how long the response is considered fresh by shared caches (CDNs, proxies)
+	SMaxAge time.Duration
+	// StaleWhileRevalidate is how long a stale response may be served while it is revalidated in the background
+	StaleWhileRevalidate time.Duration
+	// Vary lists the request headers the response varies on
+	Vary []string
+}
+
+// NoStore is a CachePolicy that prevents the response from being cached
+func NoStore() CachePolicy {
+	return CachePolicy{NoStore: true}
+}
+
+// String returns the Cache-Control header value for the policy
+func (p CachePolicy) String() string {
+	directives := make([]string, 0, 9)
+	if p.Public {
+		directives = append(directives, "public")
+	}
+	if p.Private {
+		directives = append(directives, "private")
+	}
+	if p.NoCache {
+		directives = append(directives, "no-cache")
+	}
+	if p.NoStore {
+		directives = append(directives, "no-store")
+	}
+	if p.MustRevalidate {
+		directives = append(directives, "must-revalidate")
+	}
+	if p.MaxAge > 0 {
+		directives = append(directives, "max-age="+seconds(p.MaxAge))
+	}
+	if p.SMaxAge > 0 {
+		directives = append(directives, "s-maxage="+seconds(p.SMaxAge))
+	}
+	if p.StaleWhileRevalidate > 0 {
+		directives = append(directives, "stale-while-revalidate="+seconds(p.StaleWhileRevalidate))
+	}
+	if p.Immutable {
+		directives = append(directives, "immutable")
+	}
+
+	return strings.Join(directives, ", ")
+}
+
+// applyResponse implements ResponseOption
+func (p CachePolicy) applyResponse(o *responseOptions) {
+	o.cachePolicy = &p
+}
+
+// apply writes the policy to the response headers
+func (p CachePolicy) apply(h http.Header) {
+	if v := p.String(); v != "" {
+		h.Set("Cache-Control", v)
+	}
+	addVary(h, p.Vary...)
+}
+
+// addVary adds values to the Vary header, skipping any that are already present
+func addVary(h http.Header, values ...string) {
+	if len(values) == 0 {
+		return
+	}
+
+	var vary []string
+	for _, v := range h.Values("Vary") {
+		for f := range strings.SplitSeq(v, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				vary = append(vary, f)
+			}
+		}
+	}
+
+	changed := false
+	for _, v := range values {
+		if !containsFold(vary, v) {
+			vary = append(vary, http.CanonicalHeaderKey(v))
+			changed = true
+		}
+	}
+
+	if changed {
+		h.Set("Vary", strings.Join(vary, ", "))
+	}
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(d/time.Second), 10)
+}
@@ -0,0 +1,187 @@
+package httpio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCachePolicy_String(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		policy CachePolicy
+		want   string
+	}{
+		{
+			name:   "empty",
+			policy: CachePolicy{},
+			want:   "",
+		},
+		{
+			name:   "no-store",
+			policy: NoStore(),
+			want:   "no-store",
+		},
+		{
+			name: "public with ages",
+			policy: CachePolicy{
+				Public:               true,
+				MaxAge:               time.Minute,
+				SMaxAge:              time.Hour,
+				StaleWhileRevalidate: 30 * time.Second,
+			},
+			want: "public, max-age=60, s-maxage=3600, stale-while-revalidate=30",
+		},
+		{
+			name: "private immutable",
+			policy: CachePolicy{
+				Private:   true,
+				MaxAge:    365 * 24 * time.Hour,
+				Immutable: true,
+			},
+			want: "private, max-age=31536000, immutable",
+		},
+		{
+			name: "revalidate",
+			policy: CachePolicy{
+				NoCache:        true,
+				MustRevalidate: true,
+			},
+			want: "no-cache, must-revalidate",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.policy.String(); got != tt.want {
+				t.Errorf("CachePolicy.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_addVary(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		existing []string
+		values   []string
+		want     string
+	}{
+		{
+			name:   "new header",
+			values: []string{"accept-encoding"},
+			want:   "Accept-Encoding",
+		},
+		{
+			name:     "merges existing",
+			existing: []string{"Origin"},
+			values:   []string{"Accept-Encoding", "Authorization"},
+			want:     "Origin, Accept-Encoding, Authorization",
+		},
+		{
+			name:     "skips duplicates",
+			existing: []string{"Origin, accept-encoding"},
+			values:   []string{"Accept-Encoding"},
+			want:     "Origin, accept-encoding",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := http.Header{}
+			for _, v := range tt.existing {
+				h.Add("Vary", v)
+			}
+			addVary(h, tt.values...)
+
+			if got := h.Get("Vary"); got != tt.want {
+				t.Errorf("addVary() Vary = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncoder_cachePolicy(t *testing.T) {
+	t.Parallel()
+
+	publicPolicy := CachePolicy{Public: true, MaxAge: time.Minute, Vary: []string{"Authorization"}}
+
+	tests := []struct {
+		name      string
+		opts      []EncoderOption
+		write     func(e *Encoder) error
+		wantCache string
+		wantVary  string
+	}{
+		{
+			name: "Ok without policy",
+			write: func(e *Encoder) error {
+				return e.Ok("body")
+			},
+		},
+		{
+			name: "Ok with policy",
+			write: func(e *Encoder) error {
+				return e.Ok("body", publicPolicy)
+			},
+			wantCache: "public, max-age=60",
+			wantVary:  "Authorization",
+		},
+		{
+			name: "Ok with default policy",
+			opts: []EncoderOption{WithCachePolicy(publicPolicy)},
+			write: func(e *Encoder) error {
+				return e.Ok("body")
+			},
+			wantCache: "public, max-age=60",
+			wantVary:  "Authorization",
+		},
+		{
+			name: "StatusCodeWithBody overrides default policy",
+			opts: []EncoderOption{WithCachePolicy(publicPolicy)},
+			write: func(e *Encoder) error {
+				return e.StatusCodeWithBody(http.StatusAccepted, "body", CachePolicy{Private: true})
+			},
+			wantCache: "private",
+		},
+		{
+			name: "ClientMessage defaults to no-store",
+			opts: []EncoderOption{WithCachePolicy(publicPolicy)},
+			write: func(e *Encoder) error {
+				return e.ClientMessage(context.Background(), NewNotFoundMessage("missing"))
+			},
+			wantCache: "no-store",
+		},
+		{
+			name: "ClientMessage with error policy",
+			opts: []EncoderOption{WithErrorCachePolicy(CachePolicy{Public: true, MaxAge: 5 * time.Second})},
+			write: func(e *Encoder) error {
+				return e.ClientMessage(context.Background(), NewNotFound())
+			},
+			wantCache: "public, max-age=5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			_ = tt.write(NewEncoder(recorder, tt.opts...))
+
+			if got := recorder.Header().Get("Cache-Control"); got != tt.wantCache {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.wantCache)
+			}
+			if got := recorder.Header().Get("Vary"); got != tt.wantVary {
+				t.Errorf("Vary = %q, want %q", got, tt.wantVary)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package httpio
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/errors/v5"
+)
+
+// Classification describes the response an error produces when written by Encoder.ClientMessage
+type Classification struct {
+	// StatusCode is the status code of the response
+	StatusCode int
+	// MessageType is the name of the ClientMessage type, such as "notFound", or empty when the error
+	// produces a generic InternalServerError (500)
+	MessageType string
+	// Messages holds the non-empty client messages contained within the error chain
+	Messages []string
+	// HasCause reports whether the chain contains an error other than ClientMessages
+	HasCause bool
+	// Trace holds the links of the error chain, including their source locations
+	Trace []string
+}
+
+// StatusCode returns the http status code of the client message
+func (c *ClientMessage) StatusCode() int {
+	return c.msgType.statusCode()
+}
+
+// StatusCode returns the status code that Encoder.ClientMessage writes for err, including errors
+// translated by the error mappers. A nil error returns Ok (200).
+func StatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	return Classify(err).StatusCode
+}
+
+// IsClientError reports whether err produces a client error (4xx) response
+func IsClientError(err error) bool {
+	code := StatusCode(err)
+
+	return code >= 400 && code <= 499
+}
+
+// IsServerError reports whether err produces a server error (5xx) response
+func IsServerError(err error) bool {
+	code := StatusCode(err)
+
+	return code >= 500 && code <= 599
+}
+
+// Classify describes the response that Encoder.ClientMessage writes for err, including errors
+// translated by the error mappers. A nil error returns the zero Classification.
+func Classify(err error) Classification {
+	return classify(err, true)
+}
+
+// classify describes the response for err, translating it with the error mappers when mapErrors is set
+func classify(err error, mapErrors bool) Classification {
+	if err == nil {
+		return Classification{}
+	}
+
+	c := Classification{
+		StatusCode: http.StatusInternalServerError,
+		Trace:      strings.Split(err.Error(), "\n"),
+	}
+
+	if mapErrors {
+		if chain, ok := mapError(err); ok {
+			err = chain
+		}
+	}
+
+	cerr := &ClientMessage{}
+	if errors.As(err, &cerr) {
+		c.StatusCode = cerr.StatusCode()
+		c.MessageType = cerr.msgType.String()
+	}
+	for _, msg := range Messages(err) {
+		if msg != "" {
+			c.Messages = append(c.Messages, msg)
+		}
+	}
+	c.HasCause = CauseIsError(err)
+
+	return c
+}
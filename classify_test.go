@@ -0,0 +1,90 @@
+package httpio
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/errors/v5"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		err             error
+		want            Classification
+		wantClientError bool
+		wantServerError bool
+	}{
+		{
+			name: "nil",
+			want: Classification{},
+		},
+		{
+			name:            "client message",
+			err:             NewNotFoundMessage("item not found"),
+			want:            Classification{StatusCode: http.StatusNotFound, MessageType: "notFound", Messages: []string{"item not found"}},
+			wantClientError: true,
+		},
+		{
+			name: "nested client messages with cause",
+			err:  NewConflictMessageWithError(NewBadRequestMessageWithError(errors.New("cause"), "inner"), "outer"),
+			want: Classification{
+				StatusCode:  http.StatusConflict,
+				MessageType: "conflict",
+				Messages:    []string{"outer", "inner"},
+				HasCause:    true,
+			},
+			wantClientError: true,
+		},
+		{
+			name:            "server client message",
+			err:             NewServiceUnavailable(),
+			want:            Classification{StatusCode: http.StatusServiceUnavailable, MessageType: "serviceUnavailable"},
+			wantServerError: true,
+		},
+		{
+			name:            "mapped error",
+			err:             errors.Wrap(context.DeadlineExceeded, "db.Query()"),
+			want:            Classification{StatusCode: http.StatusGatewayTimeout, MessageType: "gatewayTimeout", HasCause: true},
+			wantServerError: true,
+		},
+		{
+			name:            "unmapped error",
+			err:             errors.New("boom"),
+			want:            Classification{StatusCode: http.StatusInternalServerError, HasCause: true},
+			wantServerError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := Classify(tt.err)
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(Classification{}, "Trace")); diff != "" {
+				t.Errorf("Classify() mismatch (-want +got):\n%s", diff)
+			}
+			if tt.err != nil && len(got.Trace) == 0 {
+				t.Errorf("Classify() Trace is empty")
+			}
+
+			wantStatus := tt.want.StatusCode
+			if tt.err == nil {
+				wantStatus = http.StatusOK
+			}
+			if got := StatusCode(tt.err); got != wantStatus {
+				t.Errorf("StatusCode() = %d, want %d", got, wantStatus)
+			}
+			if got := IsClientError(tt.err); got != tt.wantClientError {
+				t.Errorf("IsClientError() = %v, want %v", got, tt.wantClientError)
+			}
+			if got := IsServerError(tt.err); got != tt.wantServerError {
+				t.Errorf("IsServerError() = %v, want %v", got, tt.wantServerError)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Package client calls services that respond with httpio, decoding error responses back into ClientMessage chains.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/cccteam/httpio"
+	"github.com/go-playground/errors/v5"
+)
+
+// maxErrorBodySize is the number of bytes of an error response body that are read
+const maxErrorBodySize = 1 << 20
+
+// ResponseError is the cause of the error returned by Do for a non-2xx response. It is wrapped
+// in a ClientMessage matching the status code, so the httpio Has functions work across services.
+type ResponseError struct {
+	// StatusCode is the status code of the response
+	StatusCode int
+	// Message is the message of the httpio.MessageResponse body, if any
+	Message string
+	// TraceID is the trace ID of the httpio.MessageResponse body, if any
+	TraceID string
+}
+
+// Error returns the error message
+func (e *ResponseError) Error() string {
+	msg := fmt.Sprintf("response status %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		msg += fmt.Sprintf(": %q", e.Message)
+	}
+	if e.TraceID != "" {
+		msg += fmt.Sprintf(" (traceId=%s)", e.TraceID)
+	}
+
+	return msg
+}
+
+// Do sends the request and decodes a 2xx JSON response body into a new value of type T.
+// An empty body, such as that of a 204 No Content, returns the zero value.
+//
+// A non-2xx response is returned as a ClientMessage with the type, message and trace ID from the
+// httpio.MessageResponse body, so it can be checked with the httpio Has functions, returned as is to
+// propagate it, or translated with Remap. The ResponseError can be retrieved with errors.As.
+//
+// Example usage:
+//
+//	item, err := client.Do[Item](ctx, http.DefaultClient, req)
+//	if httpio.HasNotFound(err) {
+//		// handle a missing item
+//	}
+func Do[T any](ctx context.Context, c *http.Client, req *http.Request) (T, error) {
+	var v T
+
+	resp, err := c.Do(req.WithContext(ctx))
+	if err != nil {
+		return v, errors.Wrap(err, "http.Client.Do()")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return v, responseError(resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil && !errors.Is(err, io.EOF) {
+		return v, errors.Wrap(err, "json.Decoder.Decode()")
+	}
+
+	return v, nil
+}
+
+// responseError builds the ClientMessage chain for a non-2xx response
+func responseError(resp *http.Response) error {
+	rerr := &ResponseError{StatusCode: resp.StatusCode}
+
+	var body httpio.MessageResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxErrorBodySize)).Decode(&body); err == nil {
+		rerr.Message = body.Message
+		rerr.TraceID = body.TraceID
+	}
+
+	return httpio.NewClientMessage(resp.StatusCode, rerr, rerr.Message)
+}
+
+// Remap translates the status code of an error returned by Do using statusCodes, which maps a
+// downstream status code to the status code to respond with. The ResponseError and its message are kept.
+// Errors that are not from a response, or whose status code is not mapped, are returned unchanged.
+//
+// Example usage:
+//
+//	// a downstream 503 Service Unavailable becomes our 502 Bad Gateway
+//	err = client.Remap(err, map[int]int{http.StatusServiceUnavailable: http.StatusBadGateway})
+func Remap(err error, statusCodes map[int]int) error {
+	var rerr *ResponseError
+	if !errors.As(err, &rerr) {
+		return err
+	}
+
+	statusCode, ok := statusCodes[rerr.StatusCode]
+	if !ok {
+		return err
+	}
+
+	return httpio.NewClientMessage(statusCode, rerr, rerr.Message)
+}
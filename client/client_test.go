@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cccteam/httpio"
+	"github.com/go-playground/errors/v5"
+	"github.com/google/go-cmp/cmp"
+)
+
+type item struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, _ *http.Request) {
+		_ = httpio.NewEncoder(w).Ok(item{ID: 1, Name: "widget"})
+	})
+	mux.HandleFunc("/empty", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		_ = httpio.NewEncoder(w).NotFoundMessage(r.Context(), "item not found")
+	})
+	mux.HandleFunc("/unavailable", func(w http.ResponseWriter, r *http.Request) {
+		_ = httpio.NewEncoder(w).ServiceUnavailableMessage(r.Context(), "try again later")
+	})
+	mux.HandleFunc("/teapot", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("not json"))
+	})
+	mux.HandleFunc("/trace", func(w http.ResponseWriter, _ *http.Request) {
+		_ = httpio.NewEncoder(w).StatusCodeWithBody(http.StatusConflict, httpio.MessageResponse{Message: "item exists", TraceID: "abc123"})
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestDo(t *testing.T) {
+	t.Parallel()
+
+	srv := newServer(t)
+
+	tests := []struct {
+		name        string
+		path        string
+		want        item
+		wantErr     bool
+		wantHas     func(err error) bool
+		wantMessage string
+		wantTraceID string
+	}{
+		{name: "ok", path: "/ok", want: item{ID: 1, Name: "widget"}},
+		{name: "empty", path: "/empty"},
+		{name: "not found", path: "/missing", wantErr: true, wantHas: httpio.HasNotFound, wantMessage: "item not found"},
+		{name: "service unavailable", path: "/unavailable", wantErr: true, wantHas: httpio.HasServiceUnavailable, wantMessage: "try again later"},
+		{name: "unmapped status", path: "/teapot", wantErr: true, wantHas: httpio.HasBadRequest},
+		{name: "trace id", path: "/trace", wantErr: true, wantHas: httpio.HasConflict, wantMessage: "item exists", wantTraceID: "abc123"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequest(http.MethodGet, srv.URL+tt.path, http.NoBody)
+			if err != nil {
+				t.Fatalf("http.NewRequest() error = %v", err)
+			}
+
+			got, err := Do[item](context.Background(), srv.Client(), req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Do() mismatch (-want +got):\n%s", diff)
+			}
+			if !tt.wantErr {
+				return
+			}
+
+			if !tt.wantHas(err) {
+				t.Errorf("Do() error = %v, does not have the expected ClientMessage type", err)
+			}
+			if got := httpio.Message(err); got != tt.wantMessage {
+				t.Errorf("Message() = %q, want %q", got, tt.wantMessage)
+			}
+
+			var rerr *ResponseError
+			if !errors.As(err, &rerr) {
+				t.Fatalf("Do() error = %v, want a ResponseError", err)
+			}
+			if rerr.TraceID != tt.wantTraceID {
+				t.Errorf("ResponseError.TraceID = %q, want %q", rerr.TraceID, tt.wantTraceID)
+			}
+		})
+	}
+}
+
+func TestRemap(t *testing.T) {
+	t.Parallel()
+
+	srv := newServer(t)
+	statusCodes := map[int]int{http.StatusServiceUnavailable: http.StatusBadGateway}
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/unavailable", http.NoBody)
+	if err != nil {
+		t.Fatalf("http.NewRequest() error = %v", err)
+	}
+	_, err = Do[item](context.Background(), srv.Client(), req)
+
+	remapped := Remap(err, statusCodes)
+	if !httpio.HasBadGateway(remapped) {
+		t.Errorf("Remap() = %v, want BadGateway", remapped)
+	}
+	if got := httpio.Message(remapped); got != "try again later" {
+		t.Errorf("Message() = %q, want %q", got, "try again later")
+	}
+
+	recorder := httptest.NewRecorder()
+	_ = httpio.NewEncoder(recorder).ClientMessage(context.Background(), remapped)
+	if recorder.Code != http.StatusBadGateway {
+		t.Errorf("Encoder.ClientMessage() status = %d, want %d", recorder.Code, http.StatusBadGateway)
+	}
+
+	notFound := httpio.NewNotFoundMessageWithError(&ResponseError{StatusCode: http.StatusNotFound}, "missing")
+	if got := Remap(notFound, statusCodes); !httpio.HasNotFound(got) || httpio.Message(got) != "missing" {
+		t.Errorf("Remap() = %v, want the unmapped error unchanged", got)
+	}
+
+	plain := errors.New("connection refused")
+	if got := Remap(plain, statusCodes); httpio.HasClientMessage(got) || got.Error() != plain.Error() {
+		t.Errorf("Remap() = %v, want the error unchanged", got)
+	}
+}
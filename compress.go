@@ -0,0 +1,444 @@
+package httpio
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/cccteam/logger"
+	"github.com/go-playground/errors/v5"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Content encodings supported by Compress and WithCompression
+const (
+	EncodingGzip   = "gzip"
+	EncodingZstd   = "zstd"
+	EncodingBrotli = "br"
+)
+
+// defaultMinCompressSize is the number of bytes a response must reach before it is compressed
+const defaultMinCompressSize = 1024
+
+// compressor is implemented by the gzip, zstd and brotli writers
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// compressorPools holds a pool of compressors for each supported encoding
+var compressorPools = map[string]*sync.Pool{ //nolint:gochecknoglobals // pools are shared by every response
+	EncodingGzip: {New: func() any {
+		return gzip.NewWriter(nil)
+	}},
+	EncodingZstd: {New: func() any {
+		// zstd.NewWriter only fails on invalid options
+		w, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+
+		return w
+	}},
+	EncodingBrotli: {New: func() any {
+		return brotli.NewWriter(nil)
+	}},
+}
+
+// CompressOption configures response compression
+type CompressOption func(c *compressConfig)
+
+// WithMinCompressSize sets the number of bytes a response must reach before it is compressed.
+// Smaller responses are written uncompressed. The default is 1024 bytes.
+func WithMinCompressSize(n int) CompressOption {
+	return func(c *compressConfig) {
+		c.minSize = n
+	}
+}
+
+// WithEncodings sets the supported encodings in order of server preference.
+// The default is zstd, br, gzip.
+func WithEncodings(encodings ...string) CompressOption {
+	return func(c *compressConfig) {
+		c.encodings = make([]string, 0, len(encodings))
+		for _, enc := range encodings {
+			if _, ok := compressorPools[enc]; ok {
+				c.encodings = append(c.encodings, enc)
+			}
+		}
+	}
+}
+
+// WithExcludedContentTypes adds content types, or content type prefixes ending in "/", that are never compressed.
+// Event streams and common pre-compressed formats are always excluded.
+func WithExcludedContentTypes(contentTypes ...string) CompressOption {
+	return func(c *compressConfig) {
+		c.excludedTypes = append(c.excludedTypes, contentTypes...)
+	}
+}
+
+type compressConfig struct {
+	minSize       int
+	encodings     []string
+	excludedTypes []string
+}
+
+func newCompressConfig(opts []CompressOption) *compressConfig {
+	c := &compressConfig{
+		minSize:   defaultMinCompressSize,
+		encodings: []string{EncodingZstd, EncodingBrotli, EncodingGzip},
+		excludedTypes: []string{
+			"text/event-stream",
+			"image/",
+			"video/",
+			"audio/",
+			"application/zip",
+			"application/gzip",
+			"application/x-gzip",
+			"application/zstd",
+			"font/woff2",
+		},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// negotiate returns the preferred encoding accepted by the request, or an empty string
+func (c *compressConfig) negotiate(r *http.Request) string {
+	if r.Method == http.MethodHead {
+		return ""
+	}
+
+	accepted := make(map[string]float64)
+	for _, v := range r.Header.Values("Accept-Encoding") {
+		for part := range strings.SplitSeq(v, ",") {
+			name, params, _ := strings.Cut(part, ";")
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+
+			q := 1.0
+			if k, v, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(k) == "q" {
+				if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+					q = f
+				}
+			}
+			accepted[name] = q
+		}
+	}
+
+	var best string
+	var bestQ float64
+	for _, enc := range c.encodings {
+		q, ok := accepted[enc]
+		if !ok {
+			q, ok = accepted["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+
+	return best
+}
+
+// compressible reports whether a response with these headers and status code may be compressed
+func (c *compressConfig) compressible(h http.Header, statusCode int) bool {
+	if !bodyAllowed(statusCode) || statusCode == http.StatusPartialContent {
+		return false
+	}
+	if h.Get("Content-Encoding") != "" || h.Get("Content-Range") != "" {
+		return false
+	}
+
+	contentType := strings.ToLower(h.Get("Content-Type"))
+	if contentType == "image/svg+xml" || strings.HasPrefix(contentType, "image/svg+xml;") {
+		return true
+	}
+	for _, excluded := range c.excludedTypes {
+		if strings.HasSuffix(excluded, "/") && strings.HasPrefix(contentType, excluded) {
+			return false
+		}
+		if contentType == excluded || strings.HasPrefix(contentType, excluded+";") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// bodyAllowed reports whether a response with the status code may carry a body
+func bodyAllowed(statusCode int) bool {
+	switch {
+	case statusCode >= 100 && statusCode <= 199:
+		return false
+	case statusCode == http.StatusNoContent, statusCode == http.StatusNotModified:
+		return false
+	}
+
+	return true
+}
+
+// Compress returns middleware that compresses responses using the encoding negotiated from the
+// Accept-Encoding request header. Responses are streamed through a pooled compressor once they
+// reach the minimum size, and smaller responses are written uncompressed.
+//
+// Bodyless (1xx, 204, 304), partial content, event stream and already encoded responses are never compressed.
+// A call to Flush compresses whatever has been written so far, so streamed responses are not held back.
+func Compress(opts ...CompressOption) func(http.Handler) http.Handler {
+	cfg := newCompressConfig(opts)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			addVary(w.Header(), "Accept-Encoding")
+
+			encoding := cfg.negotiate(r)
+			if encoding == "" {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			cw := newCompressWriter(w, encoding, cfg)
+			defer func() {
+				if err := cw.Close(); err != nil {
+					logger.FromReq(r).Error(err)
+				}
+			}()
+
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// WithCompression enables compression of the Encoder's responses using the encoding negotiated
+// from the request's Accept-Encoding header. It has no effect when the ResponseWriter is already
+// being compressed by the Compress middleware.
+func WithCompression(r *http.Request, opts ...CompressOption) EncoderOption {
+	return func(e *Encoder) {
+		if _, ok := unwrapWriter[*compressWriter](e.w); ok {
+			return
+		}
+
+		cfg := newCompressConfig(opts)
+		addVary(e.w.Header(), "Accept-Encoding")
+
+		encoding := cfg.negotiate(r)
+		if encoding == "" {
+			return
+		}
+
+		cw := newCompressWriter(e.w, encoding, cfg)
+		e.w = cw
+		e.compressor = cw
+	}
+}
+
+// compressWriter is a http.ResponseWriter that buffers a response until it reaches the minimum
+// size, then streams the remainder through a compressor.
+type compressWriter struct {
+	http.ResponseWriter
+	cfg      *compressConfig
+	encoding string
+
+	// status holds the status code until the headers are committed
+	status int
+	// committed is set once the headers have been written to the underlying ResponseWriter
+	committed bool
+	// buf holds the response body until it reaches the minimum size
+	buf []byte
+	// comp holds the compressor once compression has started
+	comp   compressor
+	closed bool
+}
+
+func newCompressWriter(w http.ResponseWriter, encoding string, cfg *compressConfig) *compressWriter {
+	return &compressWriter{
+		ResponseWriter: w,
+		cfg:            cfg,
+		encoding:       encoding,
+		status:         http.StatusOK,
+	}
+}
+
+// Unwrap returns the underlying ResponseWriter for use by http.ResponseController
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// WriteHeader holds the status code until it is known whether the response will be compressed.
+// Responses that can never be compressed are committed immediately.
+func (cw *compressWriter) WriteHeader(statusCode int) {
+	if cw.committed {
+		return
+	}
+	if statusCode >= 100 && statusCode <= 199 && statusCode != http.StatusSwitchingProtocols {
+		cw.ResponseWriter.WriteHeader(statusCode)
+
+		return
+	}
+
+	cw.status = statusCode
+	if !cw.cfg.compressible(cw.Header(), statusCode) {
+		_ = cw.commitUncompressed()
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if cw.closed {
+		return 0, errors.New("compressWriter: write after close")
+	}
+
+	if !cw.committed {
+		if !cw.cfg.compressible(cw.Header(), cw.status) {
+			if err := cw.commitUncompressed(); err != nil {
+				return 0, err
+			}
+		} else {
+			cw.buf = append(cw.buf, p...)
+			if len(cw.buf) < cw.cfg.minSize {
+				return len(p), nil
+			}
+
+			if err := cw.startCompression(); err != nil {
+				return 0, err
+			}
+
+			return len(p), nil
+		}
+	}
+
+	if cw.comp != nil {
+		n, err := cw.comp.Write(p)
+		if err != nil {
+			return n, errors.Wrap(err, "compressor.Write()")
+		}
+
+		return n, nil
+	}
+
+	n, err := cw.ResponseWriter.Write(p)
+	if err != nil {
+		return n, errors.Wrap(err, "http.ResponseWriter.Write()")
+	}
+
+	return n, nil
+}
+
+// Flush sends any buffered data to the client. Compression starts on the first flush regardless of
+// the minimum size so that streamed responses are delivered as they are written.
+func (cw *compressWriter) Flush() {
+	if cw.closed {
+		return
+	}
+
+	if !cw.committed {
+		if cw.cfg.compressible(cw.Header(), cw.status) {
+			_ = cw.startCompression()
+		} else {
+			_ = cw.commitUncompressed()
+		}
+	}
+
+	if cw.comp != nil {
+		_ = cw.comp.Flush()
+	}
+
+	_ = http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Close completes the response, writing any buffered data and releasing the compressor
+func (cw *compressWriter) Close() error {
+	if cw.closed {
+		return nil
+	}
+	cw.closed = true
+
+	if !cw.committed {
+		return cw.commitUncompressed()
+	}
+
+	if cw.comp == nil {
+		return nil
+	}
+
+	err := cw.comp.Close()
+	cw.comp.Reset(nil)
+	compressorPools[cw.encoding].Put(cw.comp)
+	cw.comp = nil
+	if err != nil {
+		return errors.Wrap(err, "compressor.Close()")
+	}
+
+	return nil
+}
+
+// startCompression commits the headers for a compressed response and writes any buffered data to the compressor
+func (cw *compressWriter) startCompression() error {
+	cw.commit(true)
+
+	if len(cw.buf) == 0 {
+		return nil
+	}
+
+	_, err := cw.comp.Write(cw.buf)
+	cw.buf = nil
+	if err != nil {
+		return errors.Wrap(err, "compressor.Write()")
+	}
+
+	return nil
+}
+
+// commitUncompressed commits the headers for an uncompressed response and writes any buffered data
+func (cw *compressWriter) commitUncompressed() error {
+	cw.commit(false)
+
+	if len(cw.buf) == 0 {
+		return nil
+	}
+
+	_, err := cw.ResponseWriter.Write(cw.buf)
+	cw.buf = nil
+	if err != nil {
+		return errors.Wrap(err, "http.ResponseWriter.Write()")
+	}
+
+	return nil
+}
+
+// commit writes the headers to the underlying ResponseWriter
+func (cw *compressWriter) commit(compress bool) {
+	if cw.committed {
+		return
+	}
+	cw.committed = true
+
+	h := cw.Header()
+	if compress {
+		if h.Get("Content-Type") == "" && len(cw.buf) > 0 {
+			// net/http cannot sniff the type of a compressed body
+			h.Set("Content-Type", http.DetectContentType(cw.buf))
+		}
+		h.Set("Content-Encoding", cw.encoding)
+		h.Del("Content-Length")
+		if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			h.Set("ETag", "W/"+etag)
+		}
+
+		comp, ok := compressorPools[cw.encoding].Get().(compressor)
+		if !ok {
+			panic("implementation error: invalid compressor in pool for " + cw.encoding)
+		}
+		comp.Reset(cw.ResponseWriter)
+		cw.comp = comp
+	}
+
+	cw.ResponseWriter.WriteHeader(cw.status)
+}
@@ -0,0 +1,319 @@
+package httpio
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+func Test_compressConfig_negotiate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		method         string
+		acceptEncoding string
+		opts           []CompressOption
+		want           string
+	}{
+		{
+			name: "no header",
+			want: "",
+		},
+		{
+			name:           "gzip only",
+			acceptEncoding: "gzip",
+			want:           EncodingGzip,
+		},
+		{
+			name:           "server preference on tie",
+			acceptEncoding: "gzip, br, zstd",
+			want:           EncodingZstd,
+		},
+		{
+			name:           "client quality wins",
+			acceptEncoding: "gzip;q=1.0, br;q=0.5, zstd;q=0.1",
+			want:           EncodingGzip,
+		},
+		{
+			name:           "rejected encoding",
+			acceptEncoding: "gzip;q=0",
+			want:           "",
+		},
+		{
+			name:           "wildcard",
+			acceptEncoding: "*",
+			opts:           []CompressOption{WithEncodings(EncodingGzip)},
+			want:           EncodingGzip,
+		},
+		{
+			name:           "unsupported encodings",
+			acceptEncoding: "deflate, identity",
+			want:           "",
+		},
+		{
+			name:           "HEAD request",
+			method:         http.MethodHead,
+			acceptEncoding: "gzip",
+			want:           "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			r := httptest.NewRequest(method, "/", http.NoBody)
+			if tt.acceptEncoding != "" {
+				r.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+
+			if got := newCompressConfig(tt.opts).negotiate(r); got != tt.want {
+				t.Errorf("compressConfig.negotiate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompress(t *testing.T) {
+	t.Parallel()
+
+	large := strings.Repeat("compressible ", 200)
+
+	tests := []struct {
+		name            string
+		encoding        string
+		handler         http.HandlerFunc
+		wantStatus      int
+		wantEncoding    string
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:     "large body is compressed",
+			encoding: EncodingGzip,
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = io.WriteString(w, large)
+			},
+			wantStatus:   http.StatusOK,
+			wantEncoding: EncodingGzip,
+			wantBody:     large,
+		},
+		{
+			name:     "content type is detected before compressing",
+			encoding: EncodingGzip,
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = io.WriteString(w, "<html>"+large+"</html>")
+			},
+			wantStatus:      http.StatusOK,
+			wantEncoding:    EncodingGzip,
+			wantContentType: "text/html; charset=utf-8",
+			wantBody:        "<html>" + large + "</html>",
+		},
+		{
+			name:     "large body written in chunks with status",
+			encoding: EncodingBrotli,
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+				for range 10 {
+					_, _ = io.WriteString(w, large[:len(large)/10])
+				}
+			},
+			wantStatus:   http.StatusCreated,
+			wantEncoding: EncodingBrotli,
+			wantBody:     large[:len(large)/10*10],
+		},
+		{
+			name:     "small body is not compressed",
+			encoding: EncodingGzip,
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = io.WriteString(w, "small")
+			},
+			wantStatus: http.StatusAccepted,
+			wantBody:   "small",
+		},
+		{
+			name:     "no content",
+			encoding: EncodingGzip,
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:     "not modified",
+			encoding: EncodingGzip,
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotModified)
+			},
+			wantStatus: http.StatusNotModified,
+		},
+		{
+			name:     "event stream is not compressed",
+			encoding: EncodingGzip,
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				_, _ = io.WriteString(w, large)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   large,
+		},
+		{
+			name:     "already encoded",
+			encoding: EncodingGzip,
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Encoding", "identity")
+				_, _ = io.WriteString(w, large)
+			},
+			wantStatus:   http.StatusOK,
+			wantEncoding: "identity",
+			wantBody:     large,
+		},
+		{
+			name:     "flushed stream is compressed",
+			encoding: EncodingZstd,
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = io.WriteString(w, "first ")
+				_ = http.NewResponseController(w).Flush()
+				_, _ = io.WriteString(w, "second")
+			},
+			wantStatus:   http.StatusOK,
+			wantEncoding: EncodingZstd,
+			wantBody:     "first second",
+		},
+		{
+			name: "client does not accept encoding",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = io.WriteString(w, large)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   large,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			if tt.encoding != "" {
+				r.Header.Set("Accept-Encoding", tt.encoding)
+			}
+			recorder := httptest.NewRecorder()
+
+			Compress()(tt.handler).ServeHTTP(recorder, r)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("Compress() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if got := recorder.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Errorf("Compress() Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+			if got := recorder.Header().Get("Content-Type"); tt.wantContentType != "" && got != tt.wantContentType {
+				t.Errorf("Compress() Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+			if got := recorder.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Compress() Vary = %q, want %q", got, "Accept-Encoding")
+			}
+			if got := decompress(t, tt.wantEncoding, recorder.Body.Bytes()); got != tt.wantBody {
+				t.Errorf("Compress() body = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestWithCompression(t *testing.T) {
+	t.Parallel()
+
+	type response struct {
+		Items []string `json:"items"`
+	}
+	body := &response{Items: strings.Fields(strings.Repeat("item ", 500))}
+
+	for _, encoding := range []string{EncodingGzip, EncodingZstd, EncodingBrotli} {
+		t.Run(encoding, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			r.Header.Set("Accept-Encoding", encoding)
+			recorder := httptest.NewRecorder()
+
+			if err := NewEncoder(recorder, WithCompression(r)).Ok(body); err != nil {
+				t.Fatalf("Encoder.Ok() error = %v", err)
+			}
+
+			if got := recorder.Header().Get("Content-Encoding"); got != encoding {
+				t.Errorf("Content-Encoding = %q, want %q", got, encoding)
+			}
+
+			want, err := json.Marshal(body)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if got := decompress(t, encoding, recorder.Body.Bytes()); got != string(want)+"\n" {
+				t.Errorf("body = %q, want %q", got, string(want)+"\n")
+			}
+		})
+	}
+
+	t.Run("small error response", func(t *testing.T) {
+		t.Parallel()
+
+		r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		r.Header.Set("Accept-Encoding", EncodingGzip)
+		recorder := httptest.NewRecorder()
+
+		_ = NewEncoder(recorder, WithCompression(r)).NotFound(r.Context())
+
+		if recorder.Code != http.StatusNotFound {
+			t.Errorf("status = %d, want %d", recorder.Code, http.StatusNotFound)
+		}
+		if got := recorder.Header().Get("Content-Encoding"); got != "" {
+			t.Errorf("Content-Encoding = %q, want empty", got)
+		}
+	})
+}
+
+func decompress(t *testing.T, encoding string, data []byte) string {
+	t.Helper()
+
+	var r io.Reader
+	switch encoding {
+	case EncodingGzip:
+		gr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("gzip.NewReader() error = %v", err)
+		}
+		r = gr
+	case EncodingZstd:
+		zr, err := zstd.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("zstd.NewReader() error = %v", err)
+		}
+		defer zr.Close()
+		r = zr
+	case EncodingBrotli:
+		r = brotli.NewReader(bytes.NewReader(data))
+	default:
+		return string(data)
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("io.ReadAll() error = %v", err)
+	}
+
+	return string(b)
+}
@@ -0,0 +1,85 @@
+package httpio
+
+import (
+	stderrors "errors"
+	"runtime"
+	"strings"
+
+	"github.com/go-playground/errors/v5"
+)
+
+// DebugResponse holds the details of an error written to error responses by an Encoder created WithDebug
+type DebugResponse struct {
+	// Messages holds all client messages contained within the error chain
+	Messages []string `json:"messages,omitempty"`
+	// Chain holds the links of the error chain, including their source locations
+	Chain []string `json:"chain,omitempty"`
+	// Frames holds the source locations recorded by the error chain
+	Frames []DebugFrame `json:"frames,omitempty"`
+}
+
+// DebugFrame is a source location recorded by an error chain
+type DebugFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// WithDebug adds a DebugResponse with the full error chain to the error responses written by the Encoder.
+// Client messages in the DebugResponse are not redacted.
+//
+// WithDebug has no effect unless the binary is built with the httpio_debug tag, so it cannot be enabled
+// by accident in production builds:
+//
+//	go run -tags httpio_debug ./cmd/server
+func WithDebug() EncoderOption {
+	return func(e *Encoder) {
+		e.debug = debugBuild
+	}
+}
+
+// newDebugResponse describes the error chain of err
+func newDebugResponse(err error) *DebugResponse {
+	return &DebugResponse{
+		Messages: Messages(err),
+		Chain:    strings.Split(err.Error(), "\n"),
+		Frames:   debugFrames(err),
+	}
+}
+
+// stackTracer is implemented by errors that record a stack, such as those from the httperr package
+type stackTracer interface {
+	StackTrace() []runtime.Frame
+}
+
+// debugFrames returns the source locations recorded by err and the errors it wraps
+func debugFrames(err error) []DebugFrame {
+	var frames []DebugFrame
+	for err != nil {
+		switch e := err.(type) { //nolint:errorlint // each error in the chain is inspected in turn
+		case errors.Chain:
+			for _, link := range e {
+				frames = append(frames, newDebugFrame(link.Source.Frame))
+			}
+			// the first link holds the root of the chain, which may record frames or wrap errors of its own
+			err = e[0].Err
+
+			continue
+		case stackTracer:
+			for _, frame := range e.StackTrace() {
+				frames = append(frames, newDebugFrame(frame))
+			}
+		}
+		err = stderrors.Unwrap(err)
+	}
+
+	return frames
+}
+
+func newDebugFrame(f runtime.Frame) DebugFrame {
+	return DebugFrame{
+		Function: f.Function,
+		File:     f.File,
+		Line:     f.Line,
+	}
+}
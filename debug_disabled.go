@@ -0,0 +1,6 @@
+//go:build !httpio_debug
+
+package httpio
+
+// debugBuild disables WithDebug in binaries built without the httpio_debug tag
+const debugBuild = false
@@ -0,0 +1,6 @@
+//go:build httpio_debug
+
+package httpio
+
+// debugBuild enables WithDebug in binaries built with the httpio_debug tag
+const debugBuild = true
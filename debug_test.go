@@ -0,0 +1,67 @@
+package httpio
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/errors/v5"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewDebugResponse(t *testing.T) {
+	t.Parallel()
+
+	err := NewBadGatewayMessageWithError(errors.Wrap(NewNotFoundMessage("upstream item"), "store.Item()"), "upstream failed")
+
+	got := newDebugResponse(err)
+	if diff := cmp.Diff([]string{"upstream failed", "upstream item"}, got.Messages); diff != "" {
+		t.Errorf("newDebugResponse() Messages mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(strings.Split(err.Error(), "\n"), got.Chain); diff != "" {
+		t.Errorf("newDebugResponse() Chain mismatch (-want +got):\n%s", diff)
+	}
+	if len(got.Frames) != 3 {
+		t.Fatalf("newDebugResponse() recorded %d frames, want 3: %v", len(got.Frames), got.Frames)
+	}
+	for _, frame := range got.Frames {
+		if frame.Function != "github.com/cccteam/httpio.TestNewDebugResponse" || !strings.HasSuffix(frame.File, "debug_test.go") {
+			t.Errorf("newDebugResponse() frame = %+v, want a frame in TestNewDebugResponse", frame)
+		}
+	}
+}
+
+func TestEncoder_WithDebug(t *testing.T) {
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	err := NewInternalServerErrorWithError(errors.New("database unavailable"))
+	_ = NewEncoder(recorder, WithDebug()).ClientMessage(context.Background(), err)
+
+	var resp MessageResponse
+	if recorder.Body.Len() > 0 {
+		if err := json.NewDecoder(recorder.Body).Decode(&resp); err != nil {
+			t.Fatalf("json.Decoder.Decode() error = %v", err)
+		}
+	}
+
+	// debugBuild is only set when the tests are run with the httpio_debug tag
+	if !debugBuild {
+		if resp.Debug != nil {
+			t.Errorf("Encoder.ClientMessage() debug = %+v, want none without the httpio_debug tag", resp.Debug)
+		}
+
+		return
+	}
+	if resp.Debug == nil {
+		t.Fatalf("Encoder.ClientMessage() debug = nil, want the error details")
+	}
+	if !strings.Contains(strings.Join(resp.Debug.Chain, "\n"), "database unavailable") {
+		t.Errorf("Encoder.ClientMessage() debug chain = %v, want the cause", resp.Debug.Chain)
+	}
+	if len(resp.Debug.Frames) == 0 {
+		t.Errorf("Encoder.ClientMessage() debug frames are empty")
+	}
+}
@@ -0,0 +1,223 @@
+package httpio
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/errors/v5"
+)
+
+// defaultMaxBytes is the default limit on the size of a request body read by Decode
+const defaultMaxBytes = 1 << 20
+
+// ValidatorFunc validates a decoded request body and returns an error if validation fails
+type ValidatorFunc func(v any) error
+
+// DecodeOption configures Decode
+type DecodeOption func(c *decodeConfig)
+
+// MaxBytes limits the size of the request body. Larger bodies are rejected with a
+// RequestEntityTooLarge (413) message. The default is 1 MiB, and a limit of zero or less disables the check.
+// The server is only told to close the connection, rather than leave the rest of a larger body unread,
+// when the ResponseWriter is passed with WithResponseWriter.
+func MaxBytes(n int64) DecodeOption {
+	return func(c *decodeConfig) {
+		c.maxBytes = n
+	}
+}
+
+// WithResponseWriter passes the ResponseWriter of the request to http.MaxBytesReader, so that the server
+// closes the connection after a body larger than MaxBytes. Handle sets it for every request.
+func WithResponseWriter(w http.ResponseWriter) DecodeOption {
+	return func(c *decodeConfig) {
+		c.w = w
+	}
+}
+
+// DisallowUnknownFields rejects request bodies that contain fields not present in the destination type
+func DisallowUnknownFields() DecodeOption {
+	return func(c *decodeConfig) {
+		c.disallowUnknownFields = true
+	}
+}
+
+// WithContentTypes sets the media types accepted in the Content-Type header. Other media types
+// are rejected with an UnsupportedMediaType (415) message.
+// The default accepts application/json and any media type with a +json suffix.
+func WithContentTypes(contentTypes ...string) DecodeOption {
+	return func(c *decodeConfig) {
+		c.contentTypes = contentTypes
+	}
+}
+
+// WithValidator sets a validator that is run after decoding. Validation errors are returned as an
+// UnprocessableEntity (422) message unless the validator already returned a ClientMessage.
+func WithValidator(fn ValidatorFunc) DecodeOption {
+	return func(c *decodeConfig) {
+		c.validator = fn
+	}
+}
+
+type decodeConfig struct {
+	// w is told to close the connection when the body is larger than maxBytes
+	w                     http.ResponseWriter
+	maxBytes              int64
+	disallowUnknownFields bool
+	contentTypes          []string
+	validator             ValidatorFunc
+}
+
+func newDecodeConfig(opts []DecodeOption) *decodeConfig {
+	c := &decodeConfig{
+		maxBytes: defaultMaxBytes,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Decode decodes the JSON request body into a new value of type T. All failures are returned as a ClientMessage:
+//   - a missing or unsupported Content-Type is an UnsupportedMediaType (415)
+//   - a body larger than MaxBytes is a RequestEntityTooLarge (413)
+//   - an empty body, malformed JSON, mismatched types, unknown fields and trailing data are a BadRequest (400)
+//   - a validation failure is an UnprocessableEntity (422)
+//
+// Example usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) error {
+//		req, err := httpio.Decode[MyRequest](r, httpio.DisallowUnknownFields(), httpio.WithValidator(v.Struct))
+//		if err != nil {
+//			return httpio.NewEncoder(w).ClientMessage(r.Context(), err)
+//		}
+//		// continue processing the request...
+//	}
+func Decode[T any](r *http.Request, opts ...DecodeOption) (T, error) {
+	var v T
+	if err := decodeInto(r, &v, newDecodeConfig(opts)); err != nil {
+		return v, err
+	}
+
+	return v, nil
+}
+
+func decodeInto(r *http.Request, v any, cfg *decodeConfig) error {
+	if err := checkContentType(r, cfg.contentTypes); err != nil {
+		return err
+	}
+
+	body := r.Body
+	if cfg.maxBytes > 0 {
+		body = http.MaxBytesReader(rootWriter(cfg.w), body, cfg.maxBytes)
+	}
+
+	counter := &countingReader{r: body}
+	dec := json.NewDecoder(counter)
+	if cfg.disallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+
+	if err := dec.Decode(v); err != nil {
+		return decodeError(err, counter.n)
+	}
+
+	if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return decodeError(err, counter.n)
+		}
+
+		return NewBadRequestMessage("request body must contain a single JSON value")
+	}
+
+	return validate(v, cfg.validator)
+}
+
+// validate runs the validator, if any, converting failures into an UnprocessableEntity (422) message
+func validate(v any, validator ValidatorFunc) error {
+	if validator == nil {
+		return nil
+	}
+
+	if err := validator(v); err != nil {
+		if HasClientMessage(err) {
+			return errors.Wrap(err, "validator()")
+		}
+
+		return NewUnprocessableEntityMessageWithError(err, errors.Cause(err).Error())
+	}
+
+	return nil
+}
+
+// checkContentType verifies the request's Content-Type is one of the accepted media types
+func checkContentType(r *http.Request, contentTypes []string) error {
+	header := r.Header.Get("Content-Type")
+	if header == "" {
+		return NewUnsupportedMediaTypeMessage("Content-Type header is required")
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return NewUnsupportedMediaTypeMessageWithErrorf(err, "Content-Type %q is not valid", header)
+	}
+
+	if len(contentTypes) == 0 {
+		if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+			return nil
+		}
+	}
+	for _, ct := range contentTypes {
+		if strings.EqualFold(mediaType, ct) {
+			return nil
+		}
+	}
+
+	return NewUnsupportedMediaTypeMessagef("Content-Type %q is not supported", mediaType)
+}
+
+// decodeError converts a json decoding error into a ClientMessage. read is the number of bytes
+// read from the body, which is the offset reported for a truncated body.
+func decodeError(err error, read int64) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.Is(err, io.EOF):
+		return NewBadRequestMessage("request body must not be empty")
+	case errors.As(err, &maxBytesErr):
+		return NewRequestEntityTooLargeMessageWithErrorf(err, "request body must not be larger than %d bytes", maxBytesErr.Limit)
+	case errors.As(err, &syntaxErr):
+		return NewBadRequestMessageWithErrorf(err, "request body contains malformed JSON at byte offset %d", syntaxErr.Offset)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return NewBadRequestMessageWithErrorf(err, "request body contains malformed JSON at byte offset %d", read)
+	case errors.As(err, &typeErr):
+		if typeErr.Field != "" {
+			return NewBadRequestMessageWithErrorf(err, "request body contains an invalid value for field %q at byte offset %d", typeErr.Field, typeErr.Offset)
+		}
+
+		return NewBadRequestMessageWithErrorf(err, "request body contains an invalid value at byte offset %d", typeErr.Offset)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		return NewBadRequestMessageWithErrorf(err, "request body contains unknown field %s", strings.TrimPrefix(err.Error(), "json: unknown field "))
+	}
+
+	return NewBadRequestMessageWithError(err, "failed to read request body")
+}
+
+// countingReader counts the bytes read from the underlying reader
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+
+	return n, err //nolint:wrapcheck // errors must be passed through unchanged for io.EOF handling
+}
@@ -0,0 +1,231 @@
+package httpio
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/errors/v5"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+
+	type args struct {
+		contentType string
+		body        string
+		opts        []DecodeOption
+	}
+	tests := []struct {
+		name        string
+		args        args
+		want        request
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name: "success",
+			args: args{
+				contentType: "application/json; charset=utf-8",
+				body:        `{"name":"widget","count":2}`,
+			},
+			want: request{Name: "widget", Count: 2},
+		},
+		{
+			name: "json suffix media type",
+			args: args{
+				contentType: "application/merge-patch+json",
+				body:        `{"name":"widget"}`,
+			},
+			want: request{Name: "widget"},
+		},
+		{
+			name: "unknown fields allowed by default",
+			args: args{
+				contentType: "application/json",
+				body:        `{"name":"widget","other":true}`,
+			},
+			want: request{Name: "widget"},
+		},
+		{
+			name: "missing content type",
+			args: args{
+				body: `{}`,
+			},
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantMessage: "Content-Type header is required",
+		},
+		{
+			name: "unsupported content type",
+			args: args{
+				contentType: "text/plain",
+				body:        `{}`,
+			},
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantMessage: `Content-Type "text/plain" is not supported`,
+		},
+		{
+			name: "custom content types",
+			args: args{
+				contentType: "application/x-widget",
+				body:        `{"count":1}`,
+				opts:        []DecodeOption{WithContentTypes("application/x-widget")},
+			},
+			want: request{Count: 1},
+		},
+		{
+			name: "empty body",
+			args: args{
+				contentType: "application/json",
+			},
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "request body must not be empty",
+		},
+		{
+			name: "syntax error",
+			args: args{
+				contentType: "application/json",
+				body:        `{"name": "widget",}`,
+			},
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "request body contains malformed JSON at byte offset 19",
+		},
+		{
+			name: "truncated body",
+			args: args{
+				contentType: "application/json",
+				body:        `{"name": "wid`,
+			},
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "request body contains malformed JSON at byte offset 13",
+		},
+		{
+			name: "type error",
+			args: args{
+				contentType: "application/json",
+				body:        `{"count": "two"}`,
+			},
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: `request body contains an invalid value for field "count" at byte offset 15`,
+		},
+		{
+			name: "unknown field",
+			args: args{
+				contentType: "application/json",
+				body:        `{"other": true}`,
+				opts:        []DecodeOption{DisallowUnknownFields()},
+			},
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: `request body contains unknown field "other"`,
+		},
+		{
+			name: "trailing data",
+			args: args{
+				contentType: "application/json",
+				body:        `{"name":"widget"}{"name":"other"}`,
+			},
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "request body must contain a single JSON value",
+		},
+		{
+			name: "too large",
+			args: args{
+				contentType: "application/json",
+				body:        `{"name":"` + strings.Repeat("a", 100) + `"}`,
+				opts:        []DecodeOption{MaxBytes(32)},
+			},
+			wantStatus:  http.StatusRequestEntityTooLarge,
+			wantMessage: "request body must not be larger than 32 bytes",
+		},
+		{
+			name: "validation error",
+			args: args{
+				contentType: "application/json",
+				body:        `{"count":0}`,
+				opts: []DecodeOption{WithValidator(func(v any) error {
+					if v.(*request).Count == 0 {
+						return errors.New("count is required")
+					}
+
+					return nil
+				})},
+			},
+			wantStatus:  http.StatusUnprocessableEntity,
+			wantMessage: "count is required",
+		},
+		{
+			name: "validation client message",
+			args: args{
+				contentType: "application/json",
+				body:        `{"count":0}`,
+				opts: []DecodeOption{WithValidator(func(_ any) error {
+					return NewConflictMessage("already exists")
+				})},
+			},
+			wantStatus:  http.StatusConflict,
+			wantMessage: "already exists",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.args.body))
+			if tt.args.contentType != "" {
+				r.Header.Set("Content-Type", tt.args.contentType)
+			}
+
+			got, err := Decode[request](r, tt.args.opts...)
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("Decode() error = %v", err)
+				}
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Errorf("Decode() mismatch (-want +got):\n%s", diff)
+				}
+
+				return
+			}
+
+			recorder := httptest.NewRecorder()
+			_ = NewEncoder(recorder).ClientMessage(r.Context(), err)
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("Decode() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if got := Message(err); got != tt.wantMessage {
+				t.Errorf("Decode() message = %q, want %q", got, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestDecode_withResponseWriter(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(Log(func(w http.ResponseWriter, r *http.Request) error {
+		_, err := Decode[map[string]string](r, MaxBytes(8), WithResponseWriter(w))
+
+		return NewEncoder(w).ClientMessage(r.Context(), err)
+	}))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL, "application/json", strings.NewReader(`{"name":"a long name"}`))
+	if err != nil {
+		t.Fatalf("http.Post() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("Decode() status = %d, want %d", resp.StatusCode, http.StatusRequestEntityTooLarge)
+	}
+	if !resp.Close {
+		t.Errorf("Decode() did not close the connection after a body larger than MaxBytes")
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/cccteam/logger"
 	"github.com/go-playground/errors/v5"
 )
 
@@ -15,6 +14,10 @@ import (
 type MessageResponse struct {
 	Message string `json:"message,omitempty"`
 	TraceID string `json:"traceId,omitempty"`
+	// Debug holds the details of the error when the Encoder was created WithDebug
+	Debug *DebugResponse `json:"debug,omitempty"`
+	// Messages holds the nested client messages when the Encoder was created WithNestedMessages
+	Messages []string `json:"messages,omitempty"`
 }
 
 // HTTPEncoder is an interface that is accepted when encoding http responses
@@ -29,50 +32,155 @@ type Encoder struct {
 	w http.ResponseWriter
 	// encoder holds the encoder that will write to the response
 	encoder HTTPEncoder
+	// cachePolicy holds the default cache policy for successful responses
+	cachePolicy *CachePolicy
+	// errorCachePolicy holds the cache policy for error responses, defaulting to no-store when nil
+	errorCachePolicy *CachePolicy
+	// compressor holds the compressing writer when compression is enabled
+	compressor *compressWriter
+	// metrics holds the metrics used to record encode failures, if any
+	metrics Metrics
+	// redactor holds the redactor applied to client messages, if any
+	redactor Redactor
+	// debug adds the details of the error to error responses
+	debug bool
+	// nestedMessages sends every client message in the error chain in error responses
+	nestedMessages bool
+	// primaryMessage chooses the message of error responses when nestedMessages is set
+	primaryMessage PrimaryMessage
+	// envelope wraps the bodies of responses, if set
+	envelope Envelope
+	// fieldMask prunes the bodies of successful responses, if set
+	fieldMask *fieldMask
+}
+
+// EncoderOption configures an Encoder
+type EncoderOption func(e *Encoder)
+
+// WithCachePolicy sets the default cache policy for responses written by Ok and StatusCodeWithBody.
+// A policy passed directly to Ok or StatusCodeWithBody takes precedence.
+func WithCachePolicy(p CachePolicy) EncoderOption {
+	return func(e *Encoder) {
+		e.cachePolicy = &p
+	}
+}
+
+// WithErrorCachePolicy overrides the cache policy for error responses, which defaults to no-store
+func WithErrorCachePolicy(p CachePolicy) EncoderOption {
+	return func(e *Encoder) {
+		e.errorCachePolicy = &p
+	}
+}
+
+// ResponseOption configures a single response written by the Encoder
+type ResponseOption interface {
+	applyResponse(o *responseOptions)
+}
+
+// responseOptions holds the resolved options for a single response
+type responseOptions struct {
+	cachePolicy *CachePolicy
+	meta        any
 }
 
 // NewEncoder returns a new Encoder to write to the ResponseWriter
 // This encoder will write to the ResponseWriter using a json encoder.
-func NewEncoder(w http.ResponseWriter) *Encoder {
+func NewEncoder(w http.ResponseWriter, opts ...EncoderOption) *Encoder {
 	w.Header().Set("Content-Type", "application/json")
 
-	return &Encoder{
-		encoder: json.NewEncoder(w),
-		w:       w,
+	e := &Encoder{
+		w: w,
 	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	e.encoder = json.NewEncoder(e.w)
+
+	return e
+}
+
+// applyResponseOptions writes the headers for the response options, falling back to the Encoder defaults,
+// and returns the resolved options
+func (e *Encoder) applyResponseOptions(opts []ResponseOption) *responseOptions {
+	o := &responseOptions{
+		cachePolicy: e.cachePolicy,
+	}
+	for _, opt := range opts {
+		opt.applyResponse(o)
+	}
+
+	if o.cachePolicy != nil {
+		o.cachePolicy.apply(e.w.Header())
+	}
+
+	return o
 }
 
 // encode attempts to encode and write to the response writer
 func (e *Encoder) encode(body interface{}, skipFrames uint) error {
 	if body == nil {
-		return nil
+		return e.closeCompressor(skipFrames + 1)
 	}
 
 	if err := e.encoder.Encode(body); err != nil {
+		if e.metrics != nil {
+			e.metrics.IncEncodeFailure()
+		}
+
 		// If we fail to encode the response, we need to write a 500 status code.
 		// This isn't guaranteed to be written if the encoder has already written to the response body,
 		// but it will at least catch some cases
 		e.w.WriteHeader(http.StatusInternalServerError)
+		_ = e.closeCompressor(skipFrames + 1)
 
 		return errors.WrapSkipFrames(err, "encoder.Encode()", skipFrames)
 	}
 
+	return e.closeCompressor(skipFrames + 1)
+}
+
+// closeCompressor completes the response when compression is enabled
+func (e *Encoder) closeCompressor(skipFrames uint) error {
+	if e.compressor == nil {
+		return nil
+	}
+
+	if err := e.compressor.Close(); err != nil {
+		return errors.WrapSkipFrames(err, "compressWriter.Close()", skipFrames)
+	}
+
 	return nil
 }
 
-// statusCodeWithMessage writes a statusCode and message to the response header and returns the original error
+// statusCodeWithMessage writes a statusCode, message and any nested messages to the response and returns the original error
 // This also attempts to include a trace ID in the response if it exists, for debugging purposes
-func (e *Encoder) statusCodeWithMessage(ctx context.Context, statusCode int, err error, message string) error {
+func (e *Encoder) statusCodeWithMessage(ctx context.Context, statusCode int, err error, message string, messages ...string) error {
+	if e.errorCachePolicy != nil {
+		e.errorCachePolicy.apply(e.w.Header())
+	} else {
+		NoStore().apply(e.w.Header())
+	}
 	e.w.WriteHeader(statusCode)
 
-	traceID := logger.FromCtx(ctx).TraceID()
+	traceID := requestTraceID(ctx)
 
-	// if we don't have any message or traceID, we don't need to write anything to the body
-	if message == "" && traceID == "" {
-		return err
+	var debug *DebugResponse
+	if e.debug && err != nil {
+		debug = newDebugResponse(err)
 	}
 
-	if err := e.encode(&MessageResponse{Message: message, TraceID: traceID}, 4); err != nil {
+	var body any
+	resp := &MessageResponse{Message: message, Messages: messages, TraceID: traceID, Debug: debug}
+	switch {
+	case e.envelope != nil:
+		body = envelopeError(e.envelope, statusCode, resp, err)
+	case message == "" && len(messages) == 0 && traceID == "" && debug == nil:
+		// if we don't have any message, traceID or debug details, we don't need to write anything to the body
+	default:
+		body = resp
+	}
+
+	if err := e.encode(body, 4); err != nil {
 		return err
 	}
 
@@ -80,15 +188,28 @@ func (e *Encoder) statusCodeWithMessage(ctx context.Context, statusCode int, err
 }
 
 // StatusCodeWithBody writes a statusCode and body
-func (e *Encoder) StatusCodeWithBody(statusCode int, body interface{}) error {
+func (e *Encoder) StatusCodeWithBody(statusCode int, body interface{}, opts ...ResponseOption) error {
+	body, err := e.maskBody(body)
+	if err != nil {
+		return e.writeMaskError(err)
+	}
+
+	o := e.applyResponseOptions(opts)
 	e.w.WriteHeader(statusCode)
 
-	return e.encode(body, 2)
+	return e.encode(e.envelopeBody(statusCode, body, o.meta), 2)
 }
 
 // Ok returns a default http 200 status response with a body
-func (e *Encoder) Ok(body interface{}) error {
-	return e.encode(body, 2)
+func (e *Encoder) Ok(body interface{}, opts ...ResponseOption) error {
+	body, err := e.maskBody(body)
+	if err != nil {
+		return e.writeMaskError(err)
+	}
+
+	o := e.applyResponseOptions(opts)
+
+	return e.encode(e.envelopeBody(http.StatusOK, body, o.meta), 2)
 }
 
 // BadRequest creates a new empty client message with a BadRequest (400) return code
@@ -161,6 +282,13 @@ func (e *Encoder) UnsupportedMediaType(ctx context.Context) error {
 	}, "")
 }
 
+// RequestedRangeNotSatisfiable creates a new empty client message with a RequestedRangeNotSatisfiable (416) return code
+func (e *Encoder) RequestedRangeNotSatisfiable(ctx context.Context) error {
+	return e.clientMessage(ctx, &ClientMessage{
+		msgType: requestedRangeNotSatisfiable,
+	}, "")
+}
+
 // UnprocessableEntity creates a new empty client message with a UnprocessableEntity (422) return code
 func (e *Encoder) UnprocessableEntity(ctx context.Context) error {
 	return e.clientMessage(ctx, &ClientMessage{
@@ -307,6 +435,15 @@ func (e *Encoder) UnsupportedMediaTypeWithError(ctx context.Context, err error)
 	}, "")
 }
 
+// RequestedRangeNotSatisfiableWithError wraps an existing error while creating a new empty client message and a RequestedRangeNotSatisfiable (416) return code
+func (e *Encoder) RequestedRangeNotSatisfiableWithError(ctx context.Context, err error) error {
+	return e.clientMessage(ctx, &ClientMessage{
+		msgType:       requestedRangeNotSatisfiable,
+		clientMessage: Message(err),
+		error:         err,
+	}, "")
+}
+
 // UnprocessableEntityWithError wraps an existing error while creating a new empty client message and a UnprocessableEntity (422) return code
 func (e *Encoder) UnprocessableEntityWithError(ctx context.Context, err error) error {
 	return e.clientMessage(ctx, &ClientMessage{
@@ -459,6 +596,14 @@ func (e *Encoder) UnsupportedMediaTypeMessage(ctx context.Context, message strin
 	}, "")
 }
 
+// RequestedRangeNotSatisfiableMessage creates a new client message with a RequestedRangeNotSatisfiable (416) return code
+func (e *Encoder) RequestedRangeNotSatisfiableMessage(ctx context.Context, message string) error {
+	return e.clientMessage(ctx, &ClientMessage{
+		msgType:       requestedRangeNotSatisfiable,
+		clientMessage: message,
+	}, "")
+}
+
 // UnprocessableEntityMessage creates a new client message with a UnprocessableEntity (422) return code
 func (e *Encoder) UnprocessableEntityMessage(ctx context.Context, message string) error {
 	return e.clientMessage(ctx, &ClientMessage{
@@ -603,6 +748,14 @@ func (e *Encoder) UnsupportedMediaTypeMessagef(ctx context.Context, format strin
 	}, "")
 }
 
+// RequestedRangeNotSatisfiableMessagef creates a new client message with a RequestedRangeNotSatisfiable (416) return code
+func (e *Encoder) RequestedRangeNotSatisfiableMessagef(ctx context.Context, format string, a ...any) error {
+	return e.clientMessage(ctx, &ClientMessage{
+		msgType:       requestedRangeNotSatisfiable,
+		clientMessage: fmt.Sprintf(format, a...),
+	}, "")
+}
+
 // UnprocessableEntityMessagef creates a new client message with a UnprocessableEntity (422) return code
 func (e *Encoder) UnprocessableEntityMessagef(ctx context.Context, format string, a ...any) error {
 	return e.clientMessage(ctx, &ClientMessage{
@@ -757,6 +910,15 @@ func (e *Encoder) UnsupportedMediaTypeMessageWithError(ctx context.Context, err
 	}, "")
 }
 
+// RequestedRangeNotSatisfiableMessageWithError wraps an existing error while creating a new client message with a RequestedRangeNotSatisfiable (416) return code
+func (e *Encoder) RequestedRangeNotSatisfiableMessageWithError(ctx context.Context, err error, message string) error {
+	return e.clientMessage(ctx, &ClientMessage{
+		msgType:       requestedRangeNotSatisfiable,
+		clientMessage: message,
+		error:         err,
+	}, "")
+}
+
 // UnprocessableEntityMessageWithError wraps an existing error while creating a new client message with a UnprocessableEntity (422) return code
 func (e *Encoder) UnprocessableEntityMessageWithError(ctx context.Context, err error, message string) error {
 	return e.clientMessage(ctx, &ClientMessage{
@@ -919,6 +1081,15 @@ func (e *Encoder) UnsupportedMediaTypeMessageWithErrorf(ctx context.Context, err
 	}, "")
 }
 
+// RequestedRangeNotSatisfiableMessageWithErrorf wraps an existing error while creating a new client message with a RequestedRangeNotSatisfiable (416) return code
+func (e *Encoder) RequestedRangeNotSatisfiableMessageWithErrorf(ctx context.Context, err error, format string, a ...any) error {
+	return e.clientMessage(ctx, &ClientMessage{
+		msgType:       requestedRangeNotSatisfiable,
+		clientMessage: fmt.Sprintf(format, a...),
+		error:         err,
+	}, "")
+}
+
 // UnprocessableEntityMessageWithErrorf wraps an existing error while creating a new client message with a UnprocessableEntity (422) return code
 func (e *Encoder) UnprocessableEntityMessageWithErrorf(ctx context.Context, err error, format string, a ...any) error {
 	return e.clientMessage(ctx, &ClientMessage{
@@ -992,59 +1163,49 @@ func (e *Encoder) GatewayTimeoutMessageWithErrorf(ctx context.Context, err error
 }
 
 // ClientMessage sets an http code and formats a client message based upon the
-// message type found in the error chain. If no message type is found
-// it defaults to InternalServerError (500) with no message
+// message type found in the error chain. If no message type is found the error is
+// translated by the mappers registered with RegisterErrorMapper and the built-in mappers,
+// and otherwise it defaults to InternalServerError (500) with no message
 func (e *Encoder) ClientMessage(ctx context.Context, err error) error {
 	return e.clientMessage(ctx, err, "handler error")
 }
 
 func (e *Encoder) clientMessage(ctx context.Context, err error, prefix string) error {
+	if chain, ok := mapError(err); ok {
+		err = chain
+	}
+
 	var rerr error
 	if CauseIsError(err) || Message(err) != "" {
 		rerr = errors.WrapSkipFrames(err, prefix, 2)
 	}
 
+	statusCode, message := http.StatusInternalServerError, ""
 	cerr := &ClientMessage{}
 	if errors.As(err, &cerr) {
-		switch cerr.msgType {
-		case badRequest:
-			return e.statusCodeWithMessage(ctx, http.StatusBadRequest, rerr, cerr.clientMessage)
-		case unauthorized:
-			return e.statusCodeWithMessage(ctx, http.StatusUnauthorized, rerr, cerr.clientMessage)
-		case forbidden:
-			return e.statusCodeWithMessage(ctx, http.StatusForbidden, rerr, cerr.clientMessage)
-		case notFound:
-			return e.statusCodeWithMessage(ctx, http.StatusNotFound, rerr, cerr.clientMessage)
-		case methodNotAllowed:
-			return e.statusCodeWithMessage(ctx, http.StatusMethodNotAllowed, rerr, cerr.clientMessage)
-		case notAcceptable:
-			return e.statusCodeWithMessage(ctx, http.StatusNotAcceptable, rerr, cerr.clientMessage)
-		case requestTimeout:
-			return e.statusCodeWithMessage(ctx, http.StatusRequestTimeout, rerr, cerr.clientMessage)
-		case conflict:
-			return e.statusCodeWithMessage(ctx, http.StatusConflict, rerr, cerr.clientMessage)
-		case requestEntityTooLarge:
-			return e.statusCodeWithMessage(ctx, http.StatusRequestEntityTooLarge, rerr, cerr.clientMessage)
-		case unsupportedMediaType:
-			return e.statusCodeWithMessage(ctx, http.StatusUnsupportedMediaType, rerr, cerr.clientMessage)
-		case unprocessableEntity:
-			return e.statusCodeWithMessage(ctx, http.StatusUnprocessableEntity, rerr, cerr.clientMessage)
-		case tooManyRequests:
-			return e.statusCodeWithMessage(ctx, http.StatusTooManyRequests, rerr, cerr.clientMessage)
-		case clientClosedRequest:
-			return e.statusCodeWithMessage(ctx, 499, rerr, cerr.clientMessage)
-		case internalServerError:
-			return e.statusCodeWithMessage(ctx, http.StatusInternalServerError, rerr, cerr.clientMessage)
-		case notImplemented:
-			return e.statusCodeWithMessage(ctx, http.StatusNotImplemented, rerr, cerr.clientMessage)
-		case badGateway:
-			return e.statusCodeWithMessage(ctx, http.StatusBadGateway, rerr, cerr.clientMessage)
-		case serviceUnavailable:
-			return e.statusCodeWithMessage(ctx, http.StatusServiceUnavailable, rerr, cerr.clientMessage)
-		case gatewayTimeout:
-			return e.statusCodeWithMessage(ctx, http.StatusGatewayTimeout, rerr, cerr.clientMessage)
+		statusCode, message = cerr.msgType.statusCode(), cerr.clientMessage
+	}
+
+	var messages []string
+	if e.nestedMessages {
+		messages = nestedMessages(err)
+		message = e.primaryMessage.choose(messages)
+	}
+	if e.redactor != nil {
+		message = e.redactor.Redact(message)
+		for i := range messages {
+			messages[i] = e.redactor.Redact(messages[i])
+		}
+	}
+
+	annotated := Messages(err)
+	if e.redactor != nil {
+		for i := range annotated {
+			annotated[i] = e.redactor.Redact(annotated[i])
 		}
 	}
+	annotate(ctx, err, annotated)
+	recordSpanError(ctx, err, statusCode)
 
-	return e.statusCodeWithMessage(ctx, http.StatusInternalServerError, rerr, "")
+	return e.statusCodeWithMessage(ctx, statusCode, rerr, message, messages...)
 }
@@ -1050,6 +1050,85 @@ func TestEncoder_encodeMethods(t *testing.T) {
 			wantErr:           true,
 			wantContainsError: true,
 		},
+		{
+			name: "RequestedRangeNotSatisfiable()",
+			encodeMethod: func(e *Encoder, _ string, _ []interface{}, _ error) error {
+				return e.RequestedRangeNotSatisfiable(context.Background())
+			},
+			wantStatus:        http.StatusRequestedRangeNotSatisfiable,
+			wantMessage:       "",
+			wantErr:           false,
+			wantContainsError: false,
+		},
+		{
+			name: "RequestedRangeNotSatisfiableWithError()",
+			args: args{
+				err: errors.New("Testing"),
+			},
+			encodeMethod: func(e *Encoder, _ string, _ []interface{}, err error) error {
+				return e.RequestedRangeNotSatisfiableWithError(context.Background(), err)
+			},
+			wantStatus:        http.StatusRequestedRangeNotSatisfiable,
+			wantMessage:       "",
+			wantErr:           true,
+			wantContainsError: true,
+		},
+		{
+			name: "RequestedRangeNotSatisfiableMessage()",
+			args: args{
+				message: "Testing",
+			},
+			encodeMethod: func(e *Encoder, msg string, _ []interface{}, _ error) error {
+				return e.RequestedRangeNotSatisfiableMessage(context.Background(), msg)
+			},
+			wantStatus:        http.StatusRequestedRangeNotSatisfiable,
+			wantMessage:       "Testing",
+			wantErr:           true,
+			wantContainsError: false,
+		},
+		{
+			name: "RequestedRangeNotSatisfiableMessagef",
+			args: args{
+				message: "Testing %s",
+				a:       []interface{}{"f"},
+			},
+			encodeMethod: func(e *Encoder, msg string, a []interface{}, _ error) error {
+				return e.RequestedRangeNotSatisfiableMessagef(context.Background(), msg, a...)
+			},
+			wantStatus:        http.StatusRequestedRangeNotSatisfiable,
+			wantMessage:       "Testing f",
+			wantErr:           true,
+			wantContainsError: false,
+		},
+		{
+			name: "RequestedRangeNotSatisfiableMessageWithError()",
+			args: args{
+				message: "Testing",
+				err:     errors.New("Testing"),
+			},
+			encodeMethod: func(e *Encoder, msg string, _ []interface{}, err error) error {
+				return e.RequestedRangeNotSatisfiableMessageWithError(context.Background(), err, msg)
+			},
+			wantStatus:        http.StatusRequestedRangeNotSatisfiable,
+			wantMessage:       "Testing",
+			wantErr:           true,
+			wantContainsError: true,
+		},
+		{
+			name: "RequestedRangeNotSatisfiableMessageWithErrorf",
+			args: args{
+				message: "Testing %s",
+				a:       []interface{}{"f"},
+				err:     errors.New("Testing"),
+			},
+			encodeMethod: func(e *Encoder, msg string, a []interface{}, err error) error {
+				return e.RequestedRangeNotSatisfiableMessageWithErrorf(context.Background(), err, msg, a...)
+			},
+			wantStatus:        http.StatusRequestedRangeNotSatisfiable,
+			wantMessage:       "Testing f",
+			wantErr:           true,
+			wantContainsError: true,
+		},
 		{
 			name: "UnprocessableEntity()",
 			encodeMethod: func(e *Encoder, _ string, _ []interface{}, _ error) error {
@@ -1813,6 +1892,14 @@ func TestEncoder_ClientMessage(t *testing.T) {
 			wantMessage: "Testing",
 			wantStatus:  http.StatusUnsupportedMediaType,
 		},
+		{
+			name: "RequestedRangeNotSatisfiable",
+			args: args{
+				err: NewRequestedRangeNotSatisfiableMessage("Testing"),
+			},
+			wantMessage: "Testing",
+			wantStatus:  http.StatusRequestedRangeNotSatisfiable,
+		},
 		{
 			name: "UnprocessableEntity",
 			args: args{
@@ -0,0 +1,221 @@
+package httpio
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// Envelope wraps the bodies written by an Encoder in a house style. If the Envelope also has a
+// ContentType() string method, its result is used as the Content-Type of responses.
+type Envelope interface {
+	// Body returns the value written for the body of a successful response written by Ok or StatusCodeWithBody.
+	// meta is the value passed with WithMeta, if any. It is not called when body is nil.
+	Body(statusCode int, body, meta any) any
+	// Error returns the value written for an error response, or nil to write no body
+	Error(statusCode int, resp *MessageResponse) any
+}
+
+// causeEnvelope is implemented by the Envelopes of this package that inspect the error being written
+type causeEnvelope interface {
+	errorWithCause(statusCode int, resp *MessageResponse, err error) any
+}
+
+// envelopeError wraps an error response with env
+func envelopeError(env Envelope, statusCode int, resp *MessageResponse, err error) any {
+	if ce, ok := env.(causeEnvelope); ok {
+		return ce.errorWithCause(statusCode, resp, err)
+	}
+
+	return env.Error(statusCode, resp)
+}
+
+// WithEnvelope sets the Envelope used to wrap the bodies of responses
+//
+// Example usage:
+//
+//	e := httpio.NewEncoder(w, httpio.WithEnvelope(httpio.GoogleJSONEnvelope()))
+//	return e.Ok(items, httpio.WithMeta(map[string]any{"total": total}))
+func WithEnvelope(env Envelope) EncoderOption {
+	return func(e *Encoder) {
+		e.envelope = env
+		if ct, ok := env.(interface{ ContentType() string }); ok {
+			e.w.Header().Set("Content-Type", ct.ContentType())
+		}
+	}
+}
+
+// WithMeta sets the meta value passed to the Envelope for a single response. It has no effect without an Envelope.
+func WithMeta(meta any) ResponseOption {
+	return metaOption{meta: meta}
+}
+
+type metaOption struct {
+	meta any
+}
+
+// applyResponse implements ResponseOption
+func (m metaOption) applyResponse(o *responseOptions) {
+	o.meta = m.meta
+}
+
+// envelopeBody wraps a successful response body with the Envelope, if any
+func (e *Encoder) envelopeBody(statusCode int, body, meta any) any {
+	if e.envelope == nil || body == nil {
+		return body
+	}
+
+	return e.envelope.Body(statusCode, body, meta)
+}
+
+// GoogleJSONResponse is the body written by GoogleJSONEnvelope
+type GoogleJSONResponse struct {
+	Data  any              `json:"data,omitempty"`
+	Meta  any              `json:"meta,omitempty"`
+	Error *GoogleJSONError `json:"error,omitempty"`
+}
+
+// GoogleJSONError is the error object of a GoogleJSONResponse
+type GoogleJSONError struct {
+	Code    int                   `json:"code"`
+	Message string                `json:"message"`
+	Errors  []GoogleJSONErrorItem `json:"errors,omitempty"`
+	TraceID string                `json:"traceId,omitempty"`
+	Debug   *DebugResponse        `json:"debug,omitempty"`
+}
+
+// GoogleJSONErrorItem describes one of the nested client messages of a GoogleJSONError
+type GoogleJSONErrorItem struct {
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message"`
+}
+
+// GoogleJSONEnvelope returns an Envelope following the Google JSON style guide. Successful responses are
+// written as {"data": ..., "meta": ...} and errors as {"error": {"code": ..., "message": ...}}, with the
+// status text as the message when there is no client message, and the nested messages of
+// WithNestedMessages as the errors.
+func GoogleJSONEnvelope() Envelope {
+	return googleJSONEnvelope{}
+}
+
+type googleJSONEnvelope struct{}
+
+func (googleJSONEnvelope) Body(_ int, body, meta any) any {
+	return &GoogleJSONResponse{Data: body, Meta: meta}
+}
+
+func (googleJSONEnvelope) Error(statusCode int, resp *MessageResponse) any {
+	t := msgTypeForStatus(statusCode)
+	gerr := &GoogleJSONError{
+		Code:    statusCode,
+		Message: resp.Message,
+		TraceID: resp.TraceID,
+		Debug:   resp.Debug,
+	}
+	if gerr.Message == "" {
+		gerr.Message = t.statusText()
+	}
+	for _, msg := range resp.Messages {
+		gerr.Errors = append(gerr.Errors, GoogleJSONErrorItem{Reason: t.String(), Message: msg})
+	}
+
+	return &GoogleJSONResponse{Error: gerr}
+}
+
+// JSONAPIContentType is the media type of JSON:API documents
+const JSONAPIContentType = "application/vnd.api+json"
+
+// JSONAPIDocument is the top level document written by JSONAPIEnvelope
+type JSONAPIDocument struct {
+	Data     any                `json:"data,omitempty"`
+	Included []*JSONAPIResource `json:"included,omitempty"`
+	Meta     any                `json:"meta,omitempty"`
+	Errors   []JSONAPIError     `json:"errors,omitempty"`
+}
+
+// JSONAPIError is a JSON:API error object
+type JSONAPIError struct {
+	Status string              `json:"status,omitempty"`
+	Code   string              `json:"code,omitempty"`
+	Title  string              `json:"title,omitempty"`
+	Detail string              `json:"detail,omitempty"`
+	Source *JSONAPIErrorSource `json:"source,omitempty"`
+	Meta   map[string]any      `json:"meta,omitempty"`
+}
+
+// JSONAPIEnvelope returns an Envelope that writes JSON:API documents with the application/vnd.api+json
+// content type. Successful responses are written as {"data": ..., "meta": ...} and errors as
+// {"errors": [...]}, with an error object for each of the nested messages of WithNestedMessages, or for
+// the message otherwise. The status is the status code, the code is the ClientMessage type, such as
+// "notFound", the source is set with SourcePointer or SourceParameter, and the trace ID is included in the meta.
+// Use Encoder.JSONAPI to write resource objects.
+func JSONAPIEnvelope() Envelope {
+	return jsonAPIEnvelope{}
+}
+
+type jsonAPIEnvelope struct{}
+
+func (jsonAPIEnvelope) ContentType() string {
+	return JSONAPIContentType
+}
+
+func (jsonAPIEnvelope) Body(_ int, body, meta any) any {
+	return &JSONAPIDocument{Data: body, Meta: meta}
+}
+
+func (jsonAPIEnvelope) Error(statusCode int, resp *MessageResponse) any {
+	return &JSONAPIDocument{Errors: jsonAPIErrors(statusCode, resp, nil)}
+}
+
+func (jsonAPIEnvelope) errorWithCause(statusCode int, resp *MessageResponse, err error) any {
+	return &JSONAPIDocument{Errors: jsonAPIErrors(statusCode, resp, err)}
+}
+
+// jsonAPIErrors returns the JSON:API error objects for an error response
+func jsonAPIErrors(statusCode int, resp *MessageResponse, err error) []JSONAPIError {
+	t := msgTypeForStatus(statusCode)
+	sources := jsonAPIErrorSources(err)
+	newError := func(detail string, source *JSONAPIErrorSource) JSONAPIError {
+		jerr := JSONAPIError{
+			Status: strconv.Itoa(statusCode),
+			Code:   t.String(),
+			Title:  http.StatusText(statusCode),
+			Detail: detail,
+			Source: source,
+		}
+		if t.statusCode() == statusCode {
+			jerr.Title = t.statusText()
+		}
+		if resp.TraceID != "" {
+			jerr.Meta = map[string]any{"traceId": resp.TraceID}
+		}
+		if resp.Debug != nil {
+			if jerr.Meta == nil {
+				jerr.Meta = make(map[string]any)
+			}
+			jerr.Meta["debug"] = resp.Debug
+		}
+
+		return jerr
+	}
+
+	if len(resp.Messages) == 0 {
+		var source *JSONAPIErrorSource
+		if len(sources) > 0 {
+			source = sources[0].source
+		}
+
+		return []JSONAPIError{newError(resp.Message, source)}
+	}
+
+	sources = nestedJSONAPISources(sources)
+	errs := make([]JSONAPIError, 0, len(resp.Messages))
+	for i, msg := range resp.Messages {
+		var source *JSONAPIErrorSource
+		if i < len(sources) {
+			source = sources[i].source
+		}
+		errs = append(errs, newError(msg, source))
+	}
+
+	return errs
+}
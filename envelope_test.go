@@ -0,0 +1,164 @@
+package httpio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/errors/v5"
+)
+
+type envelopeItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestEncoder_WithEnvelope(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		opts            []EncoderOption
+		write           func(e *Encoder) error
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name: "no envelope",
+			write: func(e *Encoder) error {
+				return e.Ok(envelopeItem{ID: 1, Name: "widget"}, WithMeta(map[string]int{"total": 1}))
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/json",
+			wantBody:        `{"id":1,"name":"widget"}`,
+		},
+		{
+			name: "google ok with meta",
+			opts: []EncoderOption{WithEnvelope(GoogleJSONEnvelope())},
+			write: func(e *Encoder) error {
+				return e.Ok([]envelopeItem{{ID: 1, Name: "widget"}}, WithMeta(map[string]int{"total": 1}))
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/json",
+			wantBody:        `{"data":[{"id":1,"name":"widget"}],"meta":{"total":1}}`,
+		},
+		{
+			name: "google status code with body",
+			opts: []EncoderOption{WithEnvelope(GoogleJSONEnvelope())},
+			write: func(e *Encoder) error {
+				return e.StatusCodeWithBody(http.StatusCreated, envelopeItem{ID: 2, Name: "gadget"})
+			},
+			wantStatus:      http.StatusCreated,
+			wantContentType: "application/json",
+			wantBody:        `{"data":{"id":2,"name":"gadget"}}`,
+		},
+		{
+			name: "google nil body",
+			opts: []EncoderOption{WithEnvelope(GoogleJSONEnvelope())},
+			write: func(e *Encoder) error {
+				return e.Ok(nil)
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/json",
+		},
+		{
+			name: "google error",
+			opts: []EncoderOption{WithEnvelope(GoogleJSONEnvelope())},
+			write: func(e *Encoder) error {
+				return e.NotFoundMessage(context.Background(), "item not found")
+			},
+			wantStatus:      http.StatusNotFound,
+			wantContentType: "application/json",
+			wantBody:        `{"error":{"code":404,"message":"item not found"}}`,
+		},
+		{
+			name: "google error without message",
+			opts: []EncoderOption{WithEnvelope(GoogleJSONEnvelope())},
+			write: func(e *Encoder) error {
+				return e.ClientMessage(context.Background(), errors.New("database unavailable"))
+			},
+			wantStatus:      http.StatusInternalServerError,
+			wantContentType: "application/json",
+			wantBody:        `{"error":{"code":500,"message":"Internal Server Error"}}`,
+		},
+		{
+			name: "google nested messages",
+			opts: []EncoderOption{WithEnvelope(GoogleJSONEnvelope()), WithNestedMessages(OutermostMessage)},
+			write: func(e *Encoder) error {
+				return e.ClientMessage(context.Background(), NewConflictMessageWithError(NewConflictMessage("sku is discontinued"), "order rejected"))
+			},
+			wantStatus:      http.StatusConflict,
+			wantContentType: "application/json",
+			wantBody:        `{"error":{"code":409,"message":"order rejected","errors":[{"reason":"conflict","message":"order rejected"},{"reason":"conflict","message":"sku is discontinued"}]}}`,
+		},
+		{
+			name: "json:api ok",
+			opts: []EncoderOption{WithEnvelope(JSONAPIEnvelope())},
+			write: func(e *Encoder) error {
+				return e.Ok(envelopeItem{ID: 1, Name: "widget"})
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: JSONAPIContentType,
+			wantBody:        `{"data":{"id":1,"name":"widget"}}`,
+		},
+		{
+			name: "json:api error",
+			opts: []EncoderOption{WithEnvelope(JSONAPIEnvelope())},
+			write: func(e *Encoder) error {
+				return e.ClientClosedRequest(context.Background())
+			},
+			wantStatus:      statusClientClosedRequest,
+			wantContentType: JSONAPIContentType,
+			wantBody:        `{"errors":[{"status":"499","code":"clientClosedRequest","title":"Client Closed Request"}]}`,
+		},
+		{
+			name: "json:api nested messages",
+			opts: []EncoderOption{WithEnvelope(JSONAPIEnvelope()), WithNestedMessages(OutermostMessage)},
+			write: func(e *Encoder) error {
+				return e.ClientMessage(context.Background(), NewUnprocessableEntityMessageWithError(NewBadRequestMessage("name is required"), "invalid item"))
+			},
+			wantStatus:      http.StatusUnprocessableEntity,
+			wantContentType: JSONAPIContentType,
+			wantBody:        `{"errors":[{"status":"422","code":"unprocessableEntity","title":"Unprocessable Entity","detail":"invalid item"},{"status":"422","code":"unprocessableEntity","title":"Unprocessable Entity","detail":"name is required"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			_ = tt.write(NewEncoder(recorder, tt.opts...))
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if got := recorder.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+			if got := strings.TrimSpace(recorder.Body.String()); got != tt.wantBody {
+				t.Errorf("body = %s, want %s", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestEncoder_WithEnvelope_traceID(t *testing.T) {
+	t.Parallel()
+
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = NewEncoder(w, WithEnvelope(JSONAPIEnvelope())).NotFound(r.Context())
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	r.Header.Set(RequestIDHeader, "req-123")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, r)
+
+	want := `{"errors":[{"status":"404","code":"notFound","title":"Not Found","meta":{"traceId":"req-123"}}]}`
+	if got := strings.TrimSpace(recorder.Body.String()); got != want {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+}
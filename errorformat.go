@@ -0,0 +1,13 @@
+//go:build !httpio_noerrorformat
+
+package httpio
+
+import (
+	"github.com/go-playground/errors/v5"
+)
+
+// init registers errorFormatFn with the errors package, which applies to every errors.Chain in the binary.
+// Build with the httpio_noerrorformat tag to leave the errors package format function untouched.
+func init() { //nolint:gochecknoinits // the format function has always been registered by default
+	errors.RegisterErrorFormatFn(errorFormatFn)
+}
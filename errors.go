@@ -3,36 +3,139 @@ package httpio
 import (
 	stderrors "errors"
 	"fmt"
+	"net/http"
 	"strings"
 
+	"github.com/cccteam/httpio/internal/clientmsg"
 	"github.com/go-playground/errors/v5"
 )
 
 type msgType int
 
 const (
-	badRequest            msgType = iota // http code 400
-	unauthorized                         // http code 401
-	forbidden                            // http code 403
-	notFound                             // http code 404
-	methodNotAllowed                     // http code 405
-	notAcceptable                        // http code 406
-	requestTimeout                       // http code 408
-	conflict                             // http code 409
-	requestEntityTooLarge                // http code 413
-	unsupportedMediaType                 // http code 415
-	unprocessableEntity                  // http code 422
-	tooManyRequests                      // http code 429
-	clientClosedRequest                  // http code 499
-	internalServerError                  // http code 500
-	notImplemented                       // http code 501
-	badGateway                           // http code 502
-	serviceUnavailable                   // http code 503
-	gatewayTimeout                       // http code 504
+	badRequest                   msgType = iota // http code 400
+	unauthorized                                // http code 401
+	forbidden                                   // http code 403
+	notFound                                    // http code 404
+	methodNotAllowed                            // http code 405
+	notAcceptable                               // http code 406
+	requestTimeout                              // http code 408
+	conflict                                    // http code 409
+	requestEntityTooLarge                       // http code 413
+	unsupportedMediaType                        // http code 415
+	requestedRangeNotSatisfiable                // http code 416
+	unprocessableEntity                         // http code 422
+	tooManyRequests                             // http code 429
+	clientClosedRequest                         // http code 499
+	internalServerError                         // http code 500
+	notImplemented                              // http code 501
+	badGateway                                  // http code 502
+	serviceUnavailable                          // http code 503
+	gatewayTimeout                              // http code 504
 )
 
-func init() {
-	errors.RegisterErrorFormatFn(errorFormatFn)
+// statusClientClosedRequest is the non-standard status code used when the client closed the request
+const statusClientClosedRequest = 499
+
+// String returns the name of the message type
+func (t msgType) String() string {
+	switch t {
+	case badRequest:
+		return "badRequest"
+	case unauthorized:
+		return "unauthorized"
+	case forbidden:
+		return "forbidden"
+	case notFound:
+		return "notFound"
+	case methodNotAllowed:
+		return "methodNotAllowed"
+	case notAcceptable:
+		return "notAcceptable"
+	case requestTimeout:
+		return "requestTimeout"
+	case conflict:
+		return "conflict"
+	case requestEntityTooLarge:
+		return "requestEntityTooLarge"
+	case unsupportedMediaType:
+		return "unsupportedMediaType"
+	case requestedRangeNotSatisfiable:
+		return "requestedRangeNotSatisfiable"
+	case unprocessableEntity:
+		return "unprocessableEntity"
+	case tooManyRequests:
+		return "tooManyRequests"
+	case clientClosedRequest:
+		return "clientClosedRequest"
+	case internalServerError:
+		return "internalServerError"
+	case notImplemented:
+		return "notImplemented"
+	case badGateway:
+		return "badGateway"
+	case serviceUnavailable:
+		return "serviceUnavailable"
+	case gatewayTimeout:
+		return "gatewayTimeout"
+	}
+
+	return fmt.Sprintf("msgType(%d)", int(t))
+}
+
+// statusText returns the text for the message type's http status code
+func (t msgType) statusText() string {
+	if t == clientClosedRequest {
+		return "Client Closed Request"
+	}
+
+	return http.StatusText(t.statusCode())
+}
+
+// statusCode returns the http status code for the message type
+func (t msgType) statusCode() int {
+	switch t {
+	case badRequest:
+		return http.StatusBadRequest
+	case unauthorized:
+		return http.StatusUnauthorized
+	case forbidden:
+		return http.StatusForbidden
+	case notFound:
+		return http.StatusNotFound
+	case methodNotAllowed:
+		return http.StatusMethodNotAllowed
+	case notAcceptable:
+		return http.StatusNotAcceptable
+	case requestTimeout:
+		return http.StatusRequestTimeout
+	case conflict:
+		return http.StatusConflict
+	case requestEntityTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case unsupportedMediaType:
+		return http.StatusUnsupportedMediaType
+	case requestedRangeNotSatisfiable:
+		return http.StatusRequestedRangeNotSatisfiable
+	case unprocessableEntity:
+		return http.StatusUnprocessableEntity
+	case tooManyRequests:
+		return http.StatusTooManyRequests
+	case clientClosedRequest:
+		return statusClientClosedRequest
+	case internalServerError:
+		return http.StatusInternalServerError
+	case notImplemented:
+		return http.StatusNotImplemented
+	case badGateway:
+		return http.StatusBadGateway
+	case serviceUnavailable:
+		return http.StatusServiceUnavailable
+	case gatewayTimeout:
+		return http.StatusGatewayTimeout
+	}
+
+	return http.StatusInternalServerError
 }
 
 // errorFormatFn implements a custom format function for the errors package
@@ -97,6 +200,8 @@ type ClientMessage struct {
 	msgType       msgType
 	clientMessage string
 	error         error
+	// sentinel is set for the exported sentinel errors, such as ErrNotFound
+	sentinel bool
 }
 
 // Message returns the client message
@@ -106,6 +211,9 @@ func (c *ClientMessage) Message() string {
 
 // Error returns the error message
 func (c *ClientMessage) Error() string {
+	if c.sentinel {
+		return c.msgType.statusText()
+	}
 	if c.error == nil && c.clientMessage == "" {
 		return ""
 	}
@@ -199,6 +307,13 @@ func NewUnsupportedMediaType() errors.Chain {
 	})
 }
 
+// NewRequestedRangeNotSatisfiable creates a new empty client message with a RequestedRangeNotSatisfiable (416) return code
+func NewRequestedRangeNotSatisfiable() errors.Chain {
+	return wrap(&ClientMessage{
+		msgType: requestedRangeNotSatisfiable,
+	})
+}
+
 // NewUnprocessableEntity creates a new empty client message with a UnprocessableEntity (422) return code
 func NewUnprocessableEntity() errors.Chain {
 	return wrap(&ClientMessage{
@@ -335,6 +450,14 @@ func NewUnsupportedMediaTypeWithError(err error) errors.Chain {
 	})
 }
 
+// NewRequestedRangeNotSatisfiableWithError wraps an existing error while creating a new empty client message and a RequestedRangeNotSatisfiable (416) return code
+func NewRequestedRangeNotSatisfiableWithError(err error) errors.Chain {
+	return wrap(&ClientMessage{
+		msgType: requestedRangeNotSatisfiable,
+		error:   err,
+	})
+}
+
 // NewUnprocessableEntityWithError wraps an existing error while creating a new empty client message and a UnprocessableEntity (422) return code
 func NewUnprocessableEntityWithError(err error) errors.Chain {
 	return wrap(&ClientMessage{
@@ -479,6 +602,14 @@ func NewUnsupportedMediaTypeMessage(message string) errors.Chain {
 	})
 }
 
+// NewRequestedRangeNotSatisfiableMessage creates a new client message with a RequestedRangeNotSatisfiable (416) return code
+func NewRequestedRangeNotSatisfiableMessage(message string) errors.Chain {
+	return wrap(&ClientMessage{
+		msgType:       requestedRangeNotSatisfiable,
+		clientMessage: message,
+	})
+}
+
 // NewUnprocessableEntityMessage creates a new client message with a UnprocessableEntity (422) return code
 func NewUnprocessableEntityMessage(message string) errors.Chain {
 	return wrap(&ClientMessage{
@@ -623,6 +754,14 @@ func NewUnsupportedMediaTypeMessagef(format string, a ...any) errors.Chain {
 	})
 }
 
+// NewRequestedRangeNotSatisfiableMessagef creates a new client message with a RequestedRangeNotSatisfiable (416) return code
+func NewRequestedRangeNotSatisfiableMessagef(format string, a ...any) errors.Chain {
+	return wrap(&ClientMessage{
+		msgType:       requestedRangeNotSatisfiable,
+		clientMessage: fmt.Sprintf(format, a...),
+	})
+}
+
 // NewUnprocessableEntityMessagef creates a new client message with a UnprocessableEntity (422) return code
 func NewUnprocessableEntityMessagef(format string, a ...any) errors.Chain {
 	return wrap(&ClientMessage{
@@ -777,6 +916,15 @@ func NewUnsupportedMediaTypeMessageWithError(err error, message string) errors.C
 	})
 }
 
+// NewRequestedRangeNotSatisfiableMessageWithError wraps an existing error while creating a new client message with a RequestedRangeNotSatisfiable (416) return code
+func NewRequestedRangeNotSatisfiableMessageWithError(err error, message string) errors.Chain {
+	return wrap(&ClientMessage{
+		msgType:       requestedRangeNotSatisfiable,
+		clientMessage: message,
+		error:         err,
+	})
+}
+
 // NewUnprocessableEntityMessageWithError wraps an existing error while creating a new client message with a UnprocessableEntity (422) return code
 func NewUnprocessableEntityMessageWithError(err error, message string) errors.Chain {
 	return wrap(&ClientMessage{
@@ -939,6 +1087,15 @@ func NewUnsupportedMediaTypeMessageWithErrorf(err error, format string, a ...any
 	})
 }
 
+// NewRequestedRangeNotSatisfiableMessageWithErrorf wraps an existing error while creating a new client message with a RequestedRangeNotSatisfiable (416) return code
+func NewRequestedRangeNotSatisfiableMessageWithErrorf(err error, format string, a ...any) errors.Chain {
+	return wrap(&ClientMessage{
+		msgType:       requestedRangeNotSatisfiable,
+		clientMessage: fmt.Sprintf(format, a...),
+		error:         err,
+	})
+}
+
 // NewUnprocessableEntityMessageWithErrorf wraps an existing error while creating a new client message with a UnprocessableEntity (422) return code
 func NewUnprocessableEntityMessageWithErrorf(err error, format string, a ...any) errors.Chain {
 	return wrap(&ClientMessage{
@@ -1111,6 +1268,16 @@ func HasUnsupportedMediaType(err error) bool {
 	return false
 }
 
+// HasRequestedRangeNotSatisfiable checks if the error contains a RequestedRangeNotSatisfiable (416) message
+func HasRequestedRangeNotSatisfiable(err error) bool {
+	cerr := &ClientMessage{}
+	if errors.As(err, &cerr) {
+		return cerr.msgType == requestedRangeNotSatisfiable
+	}
+
+	return false
+}
+
 // HasUnprocessableEntity checks if the error contains a UnprocessableEntity (422) message
 func HasUnprocessableEntity(err error) bool {
 	cerr := &ClientMessage{}
@@ -1191,6 +1358,46 @@ func HasGatewayTimeout(err error) bool {
 	return false
 }
 
+// NewClientMessage wraps an existing error while creating a new client message with the return code for statusCode.
+// Status codes without a matching client message use the code for their class: BadRequest (400)
+// for other 4xx codes and InternalServerError (500) for any other code.
+func NewClientMessage(statusCode int, err error, message string) errors.Chain {
+	return wrap(&ClientMessage{
+		msgType:       msgTypeForStatus(statusCode),
+		clientMessage: message,
+		error:         err,
+	})
+}
+
+func init() {
+	clientmsg.New = newPlainClientMessage
+}
+
+// newPlainClientMessage creates a new client message with the return code for statusCode, like NewClientMessage,
+// but returns the ClientMessage itself rather than an errors.Chain. The httperr package calls it through clientmsg.New.
+func newPlainClientMessage(statusCode int, err error, message string) error {
+	return &ClientMessage{
+		msgType:       msgTypeForStatus(statusCode),
+		clientMessage: message,
+		error:         err,
+	}
+}
+
+// msgTypeForStatus returns the message type for a http status code
+func msgTypeForStatus(statusCode int) msgType {
+	for t := badRequest; t <= gatewayTimeout; t++ {
+		if t.statusCode() == statusCode {
+			return t
+		}
+	}
+
+	if statusCode >= 400 && statusCode < 500 {
+		return badRequest
+	}
+
+	return internalServerError
+}
+
 // HasClientMessage checks if the error contains a client message
 func HasClientMessage(err error) bool {
 	cerr := &ClientMessage{}
@@ -2,6 +2,7 @@ package httpio
 
 import (
 	stderr "errors"
+	"net/http"
 	"testing"
 
 	"github.com/go-playground/errors/v5"
@@ -270,6 +271,10 @@ func TestClientMessage_Message(t *testing.T) {
 		{name: "UnsupportedMediaType (with messagef)", args: args{err: NewUnsupportedMediaTypeMessagef("msg %v", "arg")}, want: "msg arg"},
 		{name: "UnsupportedMediaType (with message and error)", args: args{err: NewUnsupportedMediaTypeMessageWithError(stderr.New("err"), "msg")}, want: "msg"},
 		{name: "UnsupportedMediaType (with message and errorf)", args: args{err: NewUnsupportedMediaTypeMessageWithErrorf(stderr.New("err"), "msg %v", "arg")}, want: "msg arg"},
+		{name: "RequestedRangeNotSatisfiable (with message)", args: args{err: NewRequestedRangeNotSatisfiableMessage("msg")}, want: "msg"},
+		{name: "RequestedRangeNotSatisfiable (with messagef)", args: args{err: NewRequestedRangeNotSatisfiableMessagef("msg %v", "arg")}, want: "msg arg"},
+		{name: "RequestedRangeNotSatisfiable (with message and error)", args: args{err: NewRequestedRangeNotSatisfiableMessageWithError(stderr.New("err"), "msg")}, want: "msg"},
+		{name: "RequestedRangeNotSatisfiable (with message and errorf)", args: args{err: NewRequestedRangeNotSatisfiableMessageWithErrorf(stderr.New("err"), "msg %v", "arg")}, want: "msg arg"},
 		{name: "UnprocessableEntity (with message)", args: args{err: NewUnprocessableEntityMessage("msg")}, want: "msg"},
 		{name: "UnprocessableEntity (with messagef)", args: args{err: NewUnprocessableEntityMessagef("msg %v", "arg")}, want: "msg arg"},
 		{name: "UnprocessableEntity (with message and error)", args: args{err: NewUnprocessableEntityMessageWithError(stderr.New("err"), "msg")}, want: "msg"},
@@ -387,6 +392,12 @@ func TestHasClientMessage(t *testing.T) {
 		{name: "UnsupportedMediaType (with messagef)", args: args{err: NewUnsupportedMediaTypeMessagef("msg %v", "arg")}, want: true},
 		{name: "UnsupportedMediaType (with message and error)", args: args{err: NewUnsupportedMediaTypeMessageWithError(stderr.New("err"), "msg")}, want: true},
 		{name: "UnsupportedMediaType (with message and errorf)", args: args{err: NewUnsupportedMediaTypeMessageWithErrorf(stderr.New("err"), "msg %v", "arg")}, want: true},
+		{name: "RequestedRangeNotSatisfiable", args: args{err: NewRequestedRangeNotSatisfiable()}, want: true},
+		{name: "RequestedRangeNotSatisfiable (with error)", args: args{err: NewRequestedRangeNotSatisfiableWithError(stderr.New("msg"))}, want: true},
+		{name: "RequestedRangeNotSatisfiable (with message)", args: args{err: NewRequestedRangeNotSatisfiableMessage("msg")}, want: true},
+		{name: "RequestedRangeNotSatisfiable (with messagef)", args: args{err: NewRequestedRangeNotSatisfiableMessagef("msg %v", "arg")}, want: true},
+		{name: "RequestedRangeNotSatisfiable (with message and error)", args: args{err: NewRequestedRangeNotSatisfiableMessageWithError(stderr.New("err"), "msg")}, want: true},
+		{name: "RequestedRangeNotSatisfiable (with message and errorf)", args: args{err: NewRequestedRangeNotSatisfiableMessageWithErrorf(stderr.New("err"), "msg %v", "arg")}, want: true},
 		{name: "UnprocessableEntity", args: args{err: NewUnprocessableEntity()}, want: true},
 		{name: "UnprocessableEntity (with error)", args: args{err: NewUnprocessableEntityWithError(stderr.New("msg"))}, want: true},
 		{name: "UnprocessableEntity (with message)", args: args{err: NewUnprocessableEntityMessage("msg")}, want: true},
@@ -737,6 +748,35 @@ func TestHasUnsupportedMediaType(t *testing.T) {
 	}
 }
 
+func TestHasRequestedRangeNotSatisfiable(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		err error
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{name: "RequestedRangeNotSatisfiable", args: args{err: NewRequestedRangeNotSatisfiable()}, want: true},
+		{name: "RequestedRangeNotSatisfiable (with error)", args: args{err: NewRequestedRangeNotSatisfiableWithError(stderr.New("msg"))}, want: true},
+		{name: "RequestedRangeNotSatisfiable (with message)", args: args{err: NewRequestedRangeNotSatisfiableMessage("msg")}, want: true},
+		{name: "RequestedRangeNotSatisfiable (with messagef)", args: args{err: NewRequestedRangeNotSatisfiableMessagef("msg %v", "arg")}, want: true},
+		{name: "RequestedRangeNotSatisfiable (with message and error)", args: args{err: NewRequestedRangeNotSatisfiableMessageWithError(stderr.New("err"), "msg")}, want: true},
+		{name: "RequestedRangeNotSatisfiable (with message and errorf)", args: args{err: NewRequestedRangeNotSatisfiableMessageWithErrorf(stderr.New("err"), "msg %v", "arg")}, want: true},
+		{name: "Other error", args: args{err: stderr.New("err")}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := HasRequestedRangeNotSatisfiable(tt.args.err); got != tt.want {
+				t.Errorf("HasRequestedRangeNotSatisfiable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestHasUnprocessableEntity(t *testing.T) {
 	t.Parallel()
 
@@ -968,3 +1008,72 @@ func TestHasGatewayTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestMsgType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		msgType        msgType
+		wantString     string
+		wantStatusCode int
+	}{
+		{msgType: badRequest, wantString: "badRequest", wantStatusCode: http.StatusBadRequest},
+		{msgType: notFound, wantString: "notFound", wantStatusCode: http.StatusNotFound},
+		{msgType: requestedRangeNotSatisfiable, wantString: "requestedRangeNotSatisfiable", wantStatusCode: http.StatusRequestedRangeNotSatisfiable},
+		{msgType: clientClosedRequest, wantString: "clientClosedRequest", wantStatusCode: 499},
+		{msgType: internalServerError, wantString: "internalServerError", wantStatusCode: http.StatusInternalServerError},
+		{msgType: gatewayTimeout, wantString: "gatewayTimeout", wantStatusCode: http.StatusGatewayTimeout},
+		{msgType: msgType(100), wantString: "msgType(100)", wantStatusCode: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantString, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.msgType.String(); got != tt.wantString {
+				t.Errorf("msgType.String() = %q, want %q", got, tt.wantString)
+			}
+			if got := tt.msgType.statusCode(); got != tt.wantStatusCode {
+				t.Errorf("msgType.statusCode() = %d, want %d", got, tt.wantStatusCode)
+			}
+		})
+	}
+}
+
+func TestNewClientMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		statusCode int
+		want       msgType
+	}{
+		{name: "matching status", statusCode: http.StatusNotFound, want: notFound},
+		{name: "client closed request", statusCode: 499, want: clientClosedRequest},
+		{name: "server status", statusCode: http.StatusGatewayTimeout, want: gatewayTimeout},
+		{name: "other client status", statusCode: http.StatusTeapot, want: badRequest},
+		{name: "other server status", statusCode: http.StatusInsufficientStorage, want: internalServerError},
+		{name: "non-error status", statusCode: http.StatusOK, want: internalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cause := stderr.New("cause")
+			err := NewClientMessage(tt.statusCode, cause, "message")
+
+			cerr := &ClientMessage{}
+			if !errors.As(err, &cerr) {
+				t.Fatalf("NewClientMessage() = %v, want a ClientMessage", err)
+			}
+			if cerr.msgType != tt.want {
+				t.Errorf("NewClientMessage() msgType = %v, want %v", cerr.msgType, tt.want)
+			}
+			if cerr.Message() != "message" {
+				t.Errorf("NewClientMessage() message = %q, want %q", cerr.Message(), "message")
+			}
+			if !errors.Is(err, cause) {
+				t.Errorf("NewClientMessage() does not wrap the cause")
+			}
+		})
+	}
+}
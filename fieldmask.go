@@ -0,0 +1,288 @@
+package httpio
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/errors/v5"
+)
+
+// fieldMaskParam is the query parameter that holds the field mask
+const fieldMaskParam = "fields"
+
+// WithFieldMask prunes the bodies written by Ok and StatusCodeWithBody to the fields listed in the fields
+// query parameter of r, such as ?fields=id,name,owner.email. Names are the json names of the fields, nested
+// fields are separated by dots, and masks apply to every element of arrays. Fields not in the body type, or
+// keys not found in any of its maps and interface values, are written as a BadRequest (400). The mask is
+// applied before any Envelope, and error responses are not masked.
+//
+// Pruned objects are written with their fields in alphabetical order.
+//
+// Example usage:
+//
+//	return httpio.NewEncoder(w, httpio.WithFieldMask(r)).Ok(user)
+func WithFieldMask(r *http.Request) EncoderOption {
+	return func(e *Encoder) {
+		paths := splitList(r.URL.Query().Get(fieldMaskParam))
+		if len(paths) == 0 {
+			return
+		}
+
+		e.fieldMask = &fieldMask{ctx: r.Context(), root: newFieldMaskNode(paths)}
+	}
+}
+
+// fieldMask holds the fields requested for a response
+type fieldMask struct {
+	// ctx is the context of the request, used when writing a mask error
+	ctx  context.Context
+	root *fieldMaskNode
+}
+
+// fieldMaskNode is a field of the mask. A node without children selects the whole field.
+type fieldMaskNode struct {
+	children map[string]*fieldMaskNode
+	// typed is set when validate checked the children against the fields of a struct
+	typed bool
+	// seen holds the keys of the objects pruned by an untyped node, which must include every child
+	// in at least one object of the body
+	seen map[string]bool
+}
+
+// newFieldMaskNode builds the tree of the dotted paths
+func newFieldMaskNode(paths []string) *fieldMaskNode {
+	root := &fieldMaskNode{children: make(map[string]*fieldMaskNode)}
+	for _, path := range paths {
+		node := root
+		for _, name := range strings.Split(path, ".") {
+			if node.children == nil {
+				// the parent is already selected as a whole
+				break
+			}
+			child, ok := node.children[name]
+			if !ok {
+				child = &fieldMaskNode{children: make(map[string]*fieldMaskNode)}
+				node.children[name] = child
+			}
+			node = child
+		}
+		// the last name of the path selects the whole field
+		node.children = nil
+	}
+
+	return root
+}
+
+// maskBody applies the field mask, if any, to body
+func (e *Encoder) maskBody(body any) (any, error) {
+	if e.fieldMask == nil || body == nil {
+		return body, nil
+	}
+
+	if err := e.fieldMask.root.validate(reflect.TypeOf(body), ""); err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "json.Marshal()")
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, errors.Wrap(err, "json.Decoder.Decode()")
+	}
+
+	v = e.fieldMask.root.prune(v)
+	if err := e.fieldMask.root.checkSeen(""); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// writeMaskError writes the error from applying the field mask
+func (e *Encoder) writeMaskError(err error) error {
+	ctx := e.fieldMask.ctx
+	e.fieldMask = nil
+
+	return e.ClientMessage(ctx, err)
+}
+
+// prune removes the fields of v that are not selected by n
+func (n *fieldMaskNode) prune(v any) any {
+	if n.children == nil {
+		return v
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		if !n.typed && n.seen == nil {
+			n.seen = make(map[string]bool)
+		}
+		for k, val := range v {
+			child, ok := n.children[k]
+			if !ok {
+				delete(v, k)
+
+				continue
+			}
+			if !n.typed {
+				n.seen[k] = true
+			}
+			v[k] = child.prune(val)
+		}
+	case []any:
+		for i := range v {
+			v[i] = n.prune(v[i])
+		}
+	}
+
+	return v
+}
+
+// checkSeen checks that every child of an untyped node was found in at least one of the objects it pruned.
+// Nodes that pruned no objects, such as those of null fields, are not checked.
+func (n *fieldMaskNode) checkSeen(path string) error {
+	for _, name := range slices.Sorted(maps.Keys(n.children)) {
+		if n.seen != nil && !n.seen[name] {
+			return unknownFieldError(joinFieldPath(path, name))
+		}
+		if err := n.children[name].checkSeen(joinFieldPath(path, name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// marshalerTypes are the interfaces of types with custom json encodings, whose fields cannot be masked
+var marshalerTypes = []reflect.Type{ //nolint:gochecknoglobals // constant value
+	reflect.TypeFor[json.Marshaler](),
+	reflect.TypeFor[encoding.TextMarshaler](),
+}
+
+// validate checks that every field selected by n exists in the json encoding of t
+func (n *fieldMaskNode) validate(t reflect.Type, path string) error {
+	if n.children == nil {
+		return nil
+	}
+
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		if implementsMarshaler(t) {
+			break
+		}
+		t = t.Elem()
+	}
+
+	var fields map[string]reflect.Type
+	switch {
+	case t.Kind() == reflect.Interface:
+		// the type of the value is not known until it is encoded
+		return nil
+	case implementsMarshaler(t):
+	case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+		for name, child := range n.children {
+			if err := child.validate(t.Elem(), joinFieldPath(path, name)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case t.Kind() == reflect.Struct:
+		fields = jsonFields(t)
+		n.typed = true
+	}
+
+	for name, child := range n.children {
+		fieldType, ok := fields[name]
+		if !ok {
+			return unknownFieldError(joinFieldPath(path, name))
+		}
+		if err := child.validate(fieldType, joinFieldPath(path, name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unknownFieldError returns the error for a field of the mask that is not in the body
+func unknownFieldError(path string) error {
+	return SourceParameter(NewBadRequestMessagef("unknown field %q in %s", path, fieldMaskParam), fieldMaskParam)
+}
+
+// implementsMarshaler reports whether t has a custom json encoding
+func implementsMarshaler(t reflect.Type) bool {
+	for _, m := range marshalerTypes {
+		if t.Implements(m) || reflect.PointerTo(t).Implements(m) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// jsonFields returns the json names of the fields of the struct t, including promoted fields of embedded structs
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || len(f.Index) > 1 && !promotedByEmbedding(t, f.Index) {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				// the fields of an untagged embedded struct are promoted
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+
+	return fields
+}
+
+// promotedByEmbedding reports whether the field at index is reached through untagged embedded structs only
+func promotedByEmbedding(t reflect.Type, index []int) bool {
+	for _, i := range index[:len(index)-1] {
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		f := t.Field(i)
+		if name, _, _ := strings.Cut(f.Tag.Get("json"), ","); !f.Anonymous || name != "" {
+			return false
+		}
+		t = f.Type
+	}
+
+	return true
+}
+
+// joinFieldPath joins a field name to its parent path
+func joinFieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
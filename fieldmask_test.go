@@ -0,0 +1,202 @@
+package httpio
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fieldMaskOwner struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type fieldMaskAudit struct {
+	CreatedBy string `json:"createdBy"`
+}
+
+type fieldMaskItem struct {
+	fieldMaskAudit
+	ID       int               `json:"id"`
+	Name     string            `json:"name"`
+	Secret   string            `json:"-"`
+	Owner    *fieldMaskOwner   `json:"owner,omitempty"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Created  time.Time         `json:"created"`
+	Untagged bool
+}
+
+func TestEncoder_WithFieldMask(t *testing.T) {
+	t.Parallel()
+
+	item := &fieldMaskItem{
+		fieldMaskAudit: fieldMaskAudit{CreatedBy: "jane"},
+		ID:             1,
+		Name:           "widget",
+		Secret:         "hidden",
+		Owner:          &fieldMaskOwner{Name: "Jane", Email: "jane@example.com"},
+		Tags:           []string{"a", "b"},
+		Labels:         map[string]string{"env": "prod", "team": "core"},
+		Created:        time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Untagged:       true,
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		opts       []EncoderOption
+		body       any
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "no mask",
+			query:      "",
+			body:       &fieldMaskOwner{Name: "Jane", Email: "jane@example.com"},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"name":"Jane","email":"jane@example.com"}`,
+		},
+		{
+			name:       "top level and nested fields",
+			query:      "fields=id,name,owner.email",
+			body:       item,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"id":1,"name":"widget","owner":{"email":"jane@example.com"}}`,
+		},
+		{
+			name:       "whole field wins over nested field",
+			query:      "fields=owner.email,owner",
+			body:       item,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"owner":{"email":"jane@example.com","name":"Jane"}}`,
+		},
+		{
+			name:       "promoted, untagged, map and marshaler fields",
+			query:      "fields=createdBy,Untagged,labels.env,created",
+			body:       item,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"Untagged":true,"created":"2026-01-02T03:04:05Z","createdBy":"jane","labels":{"env":"prod"}}`,
+		},
+		{
+			name:       "slice",
+			query:      "fields=id",
+			body:       []fieldMaskItem{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}},
+			wantStatus: http.StatusOK,
+			wantBody:   `[{"id":1},{"id":2}]`,
+		},
+		{
+			name:       "envelope",
+			query:      "fields=name",
+			opts:       []EncoderOption{WithEnvelope(GoogleJSONEnvelope())},
+			body:       item,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":{"name":"widget"}}`,
+		},
+		{
+			name:       "unknown field",
+			query:      "fields=id,price",
+			body:       item,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"message":"unknown field \"price\" in fields"}`,
+		},
+		{
+			name:       "unknown nested field",
+			query:      "fields=owner.phone",
+			body:       item,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"message":"unknown field \"owner.phone\" in fields"}`,
+		},
+		{
+			name:       "ignored field",
+			query:      "fields=Secret",
+			body:       item,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"message":"unknown field \"Secret\" in fields"}`,
+		},
+		{
+			name:       "unknown map key",
+			query:      "fields=labels.owner",
+			body:       item,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"message":"unknown field \"labels.owner\" in fields"}`,
+		},
+		{
+			name:       "map body",
+			query:      "fields=id,owner.name",
+			body:       map[string]any{"id": 1, "name": "widget", "owner": map[string]any{"name": "Jane", "email": "jane@example.com"}},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"id":1,"owner":{"name":"Jane"}}`,
+		},
+		{
+			name:       "slice elements with different map keys",
+			query:      "fields=id,labels.env",
+			body:       []fieldMaskItem{{ID: 1, Labels: map[string]string{"env": "prod"}}, {ID: 2, Labels: map[string]string{"team": "core"}}, {ID: 3}},
+			wantStatus: http.StatusOK,
+			wantBody:   `[{"id":1,"labels":{"env":"prod"}},{"id":2,"labels":{}},{"id":3}]`,
+		},
+		{
+			name:       "slice elements without a map key",
+			query:      "fields=id,labels.owner",
+			body:       []fieldMaskItem{{ID: 1, Labels: map[string]string{"env": "prod"}}, {ID: 2, Labels: map[string]string{"team": "core"}}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"message":"unknown field \"labels.owner\" in fields"}`,
+		},
+		{
+			name:       "unknown field of a map body",
+			query:      "fields=id,price",
+			body:       map[string]any{"id": 1, "name": "widget"},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"message":"unknown field \"price\" in fields"}`,
+		},
+		{
+			name:       "unknown nested field of an interface",
+			query:      "fields=owner.phone",
+			body:       map[string]any{"owner": map[string]any{"name": "Jane"}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"message":"unknown field \"owner.phone\" in fields"}`,
+		},
+		{
+			name:       "field of a marshaler",
+			query:      "fields=created.year",
+			body:       item,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"message":"unknown field \"created.year\" in fields"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/items?"+tt.query, http.NoBody)
+			recorder := httptest.NewRecorder()
+			_ = NewEncoder(recorder, append(tt.opts, WithFieldMask(r))...).Ok(tt.body)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("Encoder.Ok() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if got := strings.TrimSpace(recorder.Body.String()); got != tt.wantBody {
+				t.Errorf("Encoder.Ok() body = %s, want %s", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestEncoder_WithFieldMask_statusCodeWithBody(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodPost, "/items?fields=id", http.NoBody)
+	recorder := httptest.NewRecorder()
+	if err := NewEncoder(recorder, WithFieldMask(r)).StatusCodeWithBody(http.StatusCreated, fieldMaskItem{ID: 3, Name: "c"}); err != nil {
+		t.Fatalf("Encoder.StatusCodeWithBody() error = %v", err)
+	}
+
+	if recorder.Code != http.StatusCreated {
+		t.Errorf("Encoder.StatusCodeWithBody() status = %d, want %d", recorder.Code, http.StatusCreated)
+	}
+	if got, want := strings.TrimSpace(recorder.Body.String()), `{"id":3}`; got != want {
+		t.Errorf("Encoder.StatusCodeWithBody() body = %s, want %s", got, want)
+	}
+}
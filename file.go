@@ -0,0 +1,415 @@
+package httpio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/errors/v5"
+)
+
+// FileOption configures a response written by Encoder.File
+type FileOption func(o *fileOptions)
+
+// Attachment sends the file with an attachment Content-Disposition so browsers download it instead of displaying it
+func Attachment() FileOption {
+	return func(o *fileOptions) {
+		o.disposition = "attachment"
+	}
+}
+
+// WithFileContentType sets the Content-Type of the file. By default it is determined from the
+// file name's extension, falling back to detecting it from the file content.
+func WithFileContentType(contentType string) FileOption {
+	return func(o *fileOptions) {
+		o.contentType = contentType
+	}
+}
+
+// WithETag sets the ETag of the file, which is used for If-None-Match and If-Range requests.
+// The value must be a quoted entity tag, such as `"v1"` or `W/"v1"`.
+func WithETag(etag string) FileOption {
+	return func(o *fileOptions) {
+		o.etag = etag
+	}
+}
+
+type fileOptions struct {
+	disposition string
+	contentType string
+	etag        string
+}
+
+// httpRange is a byte range of a file
+type httpRange struct {
+	start, length int64
+}
+
+func (r httpRange) contentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.start, r.start+r.length-1, size)
+}
+
+// File writes the content of a file, supporting conditional and byte-range requests.
+//
+// A satisfiable Range header is answered with a 206 Partial Content response, using a
+// multipart/byteranges body when more than one range is requested. An unsatisfiable Range
+// is answered with a RequestedRangeNotSatisfiable (416) client message. If-Range,
+// If-None-Match and If-Modified-Since are honored using modtime and the WithETag option.
+//
+// The Content-Disposition header carries the base of name, encoded per RFC 5987 when it is not plain ASCII.
+func (e *Encoder) File(ctx context.Context, r *http.Request, name string, modtime time.Time, content io.ReadSeeker, opts ...FileOption) error {
+	o := &fileOptions{disposition: "inline"}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	size, err := content.Seek(0, io.SeekEnd)
+	if err != nil {
+		return e.InternalServerErrorWithError(ctx, errors.Wrap(err, "io.Seeker.Seek()"))
+	}
+
+	// conditional headers are evaluated before Range (RFC 9110 Section 13.2.2)
+	unmodified := notModified(r, modtime, o.etag)
+	var ranges []httpRange
+	if !unmodified {
+		ranges, err = requestedRanges(r, size, modtime, o.etag)
+		if err != nil {
+			e.w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+
+			return e.RequestedRangeNotSatisfiableMessage(ctx, err.Error())
+		}
+	}
+
+	contentType, err := fileContentType(name, content, o.contentType)
+	if err != nil {
+		return e.InternalServerErrorWithError(ctx, err)
+	}
+
+	e.applyResponseOptions(nil)
+	h := e.w.Header()
+	h.Set("Accept-Ranges", "bytes")
+	if !isZeroTime(modtime) {
+		h.Set("Last-Modified", modtime.UTC().Format(http.TimeFormat))
+	}
+	if o.etag != "" {
+		h.Set("ETag", o.etag)
+	}
+
+	if unmodified {
+		h.Del("Content-Type")
+		e.w.WriteHeader(http.StatusNotModified)
+
+		return e.closeCompressor(2)
+	}
+
+	h.Set("Content-Type", contentType)
+	h.Set("Content-Disposition", contentDisposition(o.disposition, name))
+
+	switch len(ranges) {
+	case 0:
+		h.Set("Content-Length", strconv.FormatInt(size, 10))
+		e.w.WriteHeader(http.StatusOK)
+		if err := copyRange(e.w, r, content, httpRange{start: 0, length: size}); err != nil {
+			return err
+		}
+	case 1:
+		h.Set("Content-Range", ranges[0].contentRange(size))
+		h.Set("Content-Length", strconv.FormatInt(ranges[0].length, 10))
+		e.w.WriteHeader(http.StatusPartialContent)
+		if err := copyRange(e.w, r, content, ranges[0]); err != nil {
+			return err
+		}
+	default:
+		if err := e.multipartRanges(r, content, contentType, size, ranges); err != nil {
+			return err
+		}
+	}
+
+	return e.closeCompressor(2)
+}
+
+// copyRange writes a range of content to w, skipping the body for HEAD requests
+func copyRange(w io.Writer, r *http.Request, content io.ReadSeeker, rng httpRange) error {
+	if r.Method == http.MethodHead {
+		return nil
+	}
+
+	if _, err := content.Seek(rng.start, io.SeekStart); err != nil {
+		return errors.Wrap(err, "io.Seeker.Seek()")
+	}
+	if _, err := io.CopyN(w, content, rng.length); err != nil {
+		return errors.Wrap(err, "io.CopyN()")
+	}
+
+	return nil
+}
+
+// multipartRanges writes a multipart/byteranges response for several ranges
+func (e *Encoder) multipartRanges(r *http.Request, content io.ReadSeeker, contentType string, size int64, ranges []httpRange) error {
+	mw := multipart.NewWriter(e.w)
+	e.w.Header().Set("Content-Type", "multipart/byteranges; boundary="+mw.Boundary())
+	e.w.WriteHeader(http.StatusPartialContent)
+
+	if r.Method == http.MethodHead {
+		return nil
+	}
+
+	for _, rng := range ranges {
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":  {contentType},
+			"Content-Range": {rng.contentRange(size)},
+		})
+		if err != nil {
+			return errors.Wrap(err, "multipart.Writer.CreatePart()")
+		}
+		if err := copyRange(part, r, content, rng); err != nil {
+			return err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return errors.Wrap(err, "multipart.Writer.Close()")
+	}
+
+	return nil
+}
+
+// requestedRanges returns the ranges requested by the Range header, or nil if the whole file should be sent.
+// An error is returned when the ranges cannot be satisfied.
+func requestedRanges(r *http.Request, size int64, modtime time.Time, etag string) ([]httpRange, error) {
+	header := r.Header.Get("Range")
+	if header == "" || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+		return nil, nil
+	}
+	if !ifRangeMatches(r, modtime, etag) {
+		return nil, nil
+	}
+
+	ranges, err := parseRange(header, size)
+	if err != nil {
+		return nil, err
+	}
+
+	// Clients requesting more bytes than the file holds (e.g. overlapping ranges) get the whole file instead
+	var total int64
+	for _, rng := range ranges {
+		total += rng.length
+	}
+	if total > size {
+		return nil, nil
+	}
+
+	return ranges, nil
+}
+
+// parseRange parses a Range header of the form "bytes=0-99,200-,-50"
+func parseRange(header string, size int64) ([]httpRange, error) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok {
+		return nil, fmt.Errorf("invalid range %q", header)
+	}
+
+	var ranges []httpRange
+	noOverlap := false
+	for part := range strings.SplitSeq(spec, ",") {
+		part = textproto.TrimString(part)
+		if part == "" {
+			continue
+		}
+
+		startStr, endStr, ok := strings.Cut(part, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid range %q", header)
+		}
+		startStr, endStr = textproto.TrimString(startStr), textproto.TrimString(endStr)
+
+		var rng httpRange
+		if startStr == "" {
+			// suffix range: the last n bytes
+			n, err := strconv.ParseInt(endStr, 10, 64)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid range %q", header)
+			}
+			n = min(n, size)
+			rng = httpRange{start: size - n, length: n}
+		} else {
+			start, err := strconv.ParseInt(startStr, 10, 64)
+			if err != nil || start < 0 {
+				return nil, fmt.Errorf("invalid range %q", header)
+			}
+			if start >= size {
+				noOverlap = true
+
+				continue
+			}
+
+			end := size - 1
+			if endStr != "" {
+				end, err = strconv.ParseInt(endStr, 10, 64)
+				if err != nil || start > end {
+					return nil, fmt.Errorf("invalid range %q", header)
+				}
+				end = min(end, size-1)
+			}
+			rng = httpRange{start: start, length: end - start + 1}
+		}
+
+		if rng.length == 0 {
+			noOverlap = true
+
+			continue
+		}
+		ranges = append(ranges, rng)
+	}
+
+	if len(ranges) == 0 {
+		if noOverlap {
+			return nil, fmt.Errorf("range %q is not satisfiable for a file of %d bytes", header, size)
+		}
+
+		return nil, fmt.Errorf("invalid range %q", header)
+	}
+
+	return ranges, nil
+}
+
+// ifRangeMatches reports whether the Range header should be honored given the If-Range header
+func ifRangeMatches(r *http.Request, modtime time.Time, etag string) bool {
+	ifRange := r.Header.Get("If-Range")
+	if ifRange == "" {
+		return true
+	}
+
+	if strings.HasPrefix(ifRange, `"`) || strings.HasPrefix(ifRange, "W/") {
+		// If-Range requires a strong comparison
+		return etag != "" && !strings.HasPrefix(etag, "W/") && ifRange == etag
+	}
+
+	t, err := http.ParseTime(ifRange)
+	if err != nil || isZeroTime(modtime) {
+		return false
+	}
+
+	return modtime.Truncate(time.Second).Equal(t)
+}
+
+// notModified reports whether the conditional request headers allow a 304 Not Modified response
+func notModified(r *http.Request, modtime time.Time, etag string) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		if etag == "" {
+			return false
+		}
+		for candidate := range strings.SplitSeq(inm, ",") {
+			candidate = textproto.TrimString(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || isZeroTime(modtime) {
+		return false
+	}
+	t, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+
+	return !modtime.Truncate(time.Second).After(t)
+}
+
+// fileContentType determines the Content-Type of a file, leaving content positioned at the start
+func fileContentType(name string, content io.ReadSeeker, contentType string) (string, error) {
+	if contentType != "" {
+		return contentType, nil
+	}
+
+	if ct := mime.TypeByExtension(path.Ext(name)); ct != "" {
+		return ct, nil
+	}
+
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", errors.Wrap(err, "io.Seeker.Seek()")
+	}
+
+	buf := make([]byte, sniffLen)
+	n, err := io.ReadFull(content, buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", errors.Wrap(err, "io.ReadFull()")
+	}
+
+	return http.DetectContentType(buf[:n]), nil
+}
+
+// contentDisposition formats a Content-Disposition header with an ASCII filename and,
+// when needed, an RFC 5987 encoded filename* parameter
+func contentDisposition(disposition, name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	if name == "." || name == "/" {
+		return disposition
+	}
+
+	var fallback strings.Builder
+	plain := true
+	for _, c := range name {
+		switch {
+		case c == '"' || c == '\\':
+			fallback.WriteByte('_')
+			plain = false
+		case c < 0x20 || c > 0x7e:
+			fallback.WriteByte('_')
+			plain = false
+		default:
+			fallback.WriteRune(c)
+		}
+	}
+
+	value := disposition + `; filename="` + fallback.String() + `"`
+	if plain {
+		return value
+	}
+
+	return value + "; filename*=UTF-8''" + encodeRFC5987(name)
+}
+
+// encodeRFC5987 percent-encodes every byte of s that is not an RFC 5987 attr-char
+func encodeRFC5987(s string) string {
+	const hex = "0123456789ABCDEF"
+
+	var b strings.Builder
+	for i := range len(s) {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+			b.WriteByte(c)
+		case strings.IndexByte("!#$&+-.^_`|~", c) >= 0:
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&0xf])
+		}
+	}
+
+	return b.String()
+}
+
+// isZeroTime reports whether t is unset or the Unix epoch
+func isZeroTime(t time.Time) bool {
+	return t.IsZero() || t.Equal(time.Unix(0, 0))
+}
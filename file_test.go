@@ -0,0 +1,311 @@
+package httpio
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEncoder_File(t *testing.T) {
+	t.Parallel()
+
+	const content = "0123456789abcdefghij"
+	modtime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		method     string
+		fileName   string
+		headers    map[string]string
+		opts       []FileOption
+		wantStatus int
+		wantBody   string
+		wantHeader map[string]string
+	}{
+		{
+			name:       "full file",
+			fileName:   "notes.txt",
+			wantStatus: http.StatusOK,
+			wantBody:   content,
+			wantHeader: map[string]string{
+				"Content-Type":        "text/plain; charset=utf-8",
+				"Content-Length":      "20",
+				"Content-Disposition": `inline; filename="notes.txt"`,
+				"Accept-Ranges":       "bytes",
+				"Last-Modified":       "Tue, 02 Jan 2024 03:04:05 GMT",
+			},
+		},
+		{
+			name:       "head request",
+			method:     http.MethodHead,
+			fileName:   "notes.txt",
+			wantStatus: http.StatusOK,
+			wantHeader: map[string]string{"Content-Length": "20"},
+		},
+		{
+			name:       "attachment with content type",
+			fileName:   "data",
+			opts:       []FileOption{Attachment(), WithFileContentType("application/octet-stream")},
+			wantStatus: http.StatusOK,
+			wantBody:   content,
+			wantHeader: map[string]string{
+				"Content-Type":        "application/octet-stream",
+				"Content-Disposition": `attachment; filename="data"`,
+			},
+		},
+		{
+			name:       "non-ascii file name",
+			fileName:   "dir/résumé \"final\".txt",
+			opts:       []FileOption{Attachment()},
+			wantStatus: http.StatusOK,
+			wantBody:   content,
+			wantHeader: map[string]string{
+				"Content-Disposition": `attachment; filename="r_sum_ _final_.txt"; filename*=UTF-8''r%C3%A9sum%C3%A9%20%22final%22.txt`,
+			},
+		},
+		{
+			name:       "single range",
+			fileName:   "notes.txt",
+			headers:    map[string]string{"Range": "bytes=2-5"},
+			wantStatus: http.StatusPartialContent,
+			wantBody:   "2345",
+			wantHeader: map[string]string{
+				"Content-Range":  "bytes 2-5/20",
+				"Content-Length": "4",
+			},
+		},
+		{
+			name:       "suffix range",
+			fileName:   "notes.txt",
+			headers:    map[string]string{"Range": "bytes=-3"},
+			wantStatus: http.StatusPartialContent,
+			wantBody:   "hij",
+			wantHeader: map[string]string{"Content-Range": "bytes 17-19/20"},
+		},
+		{
+			name:       "open range",
+			fileName:   "notes.txt",
+			headers:    map[string]string{"Range": "bytes=15-"},
+			wantStatus: http.StatusPartialContent,
+			wantBody:   "fghij",
+			wantHeader: map[string]string{"Content-Range": "bytes 15-19/20"},
+		},
+		{
+			name:       "if-range etag matches",
+			fileName:   "notes.txt",
+			headers:    map[string]string{"Range": "bytes=0-1", "If-Range": `"v1"`},
+			opts:       []FileOption{WithETag(`"v1"`)},
+			wantStatus: http.StatusPartialContent,
+			wantBody:   "01",
+			wantHeader: map[string]string{"ETag": `"v1"`},
+		},
+		{
+			name:       "if-range etag mismatch",
+			fileName:   "notes.txt",
+			headers:    map[string]string{"Range": "bytes=0-1", "If-Range": `"v0"`},
+			opts:       []FileOption{WithETag(`"v1"`)},
+			wantStatus: http.StatusOK,
+			wantBody:   content,
+		},
+		{
+			name:       "if-range date mismatch",
+			fileName:   "notes.txt",
+			headers:    map[string]string{"Range": "bytes=0-1", "If-Range": "Mon, 01 Jan 2024 00:00:00 GMT"},
+			wantStatus: http.StatusOK,
+			wantBody:   content,
+		},
+		{
+			name:       "if-none-match",
+			fileName:   "notes.txt",
+			headers:    map[string]string{"If-None-Match": `"v0", W/"v1"`},
+			opts:       []FileOption{WithETag(`"v1"`)},
+			wantStatus: http.StatusNotModified,
+		},
+		{
+			name:       "if-none-match with unsatisfiable range",
+			fileName:   "notes.txt",
+			headers:    map[string]string{"If-None-Match": `"v1"`, "Range": "bytes=50-60"},
+			opts:       []FileOption{WithETag(`"v1"`)},
+			wantStatus: http.StatusNotModified,
+		},
+		{
+			name:       "if-modified-since",
+			fileName:   "notes.txt",
+			headers:    map[string]string{"If-Modified-Since": "Tue, 02 Jan 2024 03:04:05 GMT"},
+			wantStatus: http.StatusNotModified,
+		},
+		{
+			name:       "modified since",
+			fileName:   "notes.txt",
+			headers:    map[string]string{"If-Modified-Since": "Mon, 01 Jan 2024 00:00:00 GMT"},
+			wantStatus: http.StatusOK,
+			wantBody:   content,
+		},
+		{
+			name:       "unsatisfiable range",
+			fileName:   "notes.txt",
+			headers:    map[string]string{"Range": "bytes=50-60"},
+			wantStatus: http.StatusRequestedRangeNotSatisfiable,
+			wantBody:   `{"message":"range \"bytes=50-60\" is not satisfiable for a file of 20 bytes"}` + "\n",
+			wantHeader: map[string]string{"Content-Range": "bytes */20", "Content-Type": "application/json"},
+		},
+		{
+			name:       "invalid range",
+			fileName:   "notes.txt",
+			headers:    map[string]string{"Range": "bytes=5-2"},
+			wantStatus: http.StatusRequestedRangeNotSatisfiable,
+			wantBody:   `{"message":"invalid range \"bytes=5-2\""}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			r := httptest.NewRequest(method, "/", http.NoBody)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			recorder := httptest.NewRecorder()
+
+			if err := NewEncoder(recorder).File(context.Background(), r, tt.fileName, modtime, strings.NewReader(content), tt.opts...); err != nil && tt.wantStatus < 400 {
+				t.Fatalf("Encoder.File() error = %v", err)
+			}
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("Encoder.File() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if got := recorder.Body.String(); got != tt.wantBody {
+				t.Errorf("Encoder.File() body = %q, want %q", got, tt.wantBody)
+			}
+			for k, want := range tt.wantHeader {
+				if got := recorder.Header().Get(k); got != want {
+					t.Errorf("Encoder.File() header %s = %q, want %q", k, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEncoder_File_multipleRanges(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	r.Header.Set("Range", "bytes=0-1, 10-12")
+	recorder := httptest.NewRecorder()
+
+	if err := NewEncoder(recorder).File(context.Background(), r, "notes.txt", time.Time{}, strings.NewReader("0123456789abcdefghij")); err != nil {
+		t.Fatalf("Encoder.File() error = %v", err)
+	}
+
+	if recorder.Code != http.StatusPartialContent {
+		t.Errorf("Encoder.File() status = %d, want %d", recorder.Code, http.StatusPartialContent)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(recorder.Header().Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("mime.ParseMediaType() error = %v", err)
+	}
+	if mediaType != "multipart/byteranges" {
+		t.Fatalf("Encoder.File() Content-Type = %q, want multipart/byteranges", mediaType)
+	}
+
+	type part struct {
+		ContentType, ContentRange, Body string
+	}
+	var got []part
+	mr := multipart.NewReader(recorder.Body, params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextPart() error = %v", err)
+		}
+		b, err := io.ReadAll(p)
+		if err != nil {
+			t.Fatalf("ReadAll() error = %v", err)
+		}
+		got = append(got, part{ContentType: p.Header.Get("Content-Type"), ContentRange: p.Header.Get("Content-Range"), Body: string(b)})
+	}
+
+	want := []part{
+		{ContentType: "text/plain; charset=utf-8", ContentRange: "bytes 0-1/20", Body: "01"},
+		{ContentType: "text/plain; charset=utf-8", ContentRange: "bytes 10-12/20", Body: "abc"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Encoder.File() parts mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestEncoder_File_compressed(t *testing.T) {
+	t.Parallel()
+
+	content := strings.Repeat("compressible file content ", 100)
+	r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	r.Header.Set("Accept-Encoding", "gzip")
+	recorder := httptest.NewRecorder()
+
+	if err := NewEncoder(recorder, WithCompression(r)).File(context.Background(), r, "notes.txt", time.Time{}, strings.NewReader(content), WithETag(`"v1"`)); err != nil {
+		t.Fatalf("Encoder.File() error = %v", err)
+	}
+
+	if got := recorder.Header().Get("Content-Encoding"); got != EncodingGzip {
+		t.Errorf("Encoder.File() Content-Encoding = %q, want %q", got, EncodingGzip)
+	}
+	if got := recorder.Header().Get("Content-Length"); got != "" {
+		t.Errorf("Encoder.File() Content-Length = %q, want none", got)
+	}
+	if got := recorder.Header().Get("ETag"); got != `W/"v1"` {
+		t.Errorf("Encoder.File() ETag = %q, want %q", got, `W/"v1"`)
+	}
+	if got := decompress(t, EncodingGzip, recorder.Body.Bytes()); got != content {
+		t.Errorf("Encoder.File() body = %q, want %q", got, content)
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		header  string
+		want    []httpRange
+		wantErr bool
+	}{
+		{name: "single", header: "bytes=0-4", want: []httpRange{{start: 0, length: 5}}},
+		{name: "end past size", header: "bytes=5-100", want: []httpRange{{start: 5, length: 5}}},
+		{name: "suffix larger than size", header: "bytes=-100", want: []httpRange{{start: 0, length: 10}}},
+		{name: "multiple", header: "bytes=0-0, -1", want: []httpRange{{start: 0, length: 1}, {start: 9, length: 1}}},
+		{name: "skips unsatisfiable", header: "bytes=20-30,1-2", want: []httpRange{{start: 1, length: 2}}},
+		{name: "wrong unit", header: "items=0-4", wantErr: true},
+		{name: "not a number", header: "bytes=a-4", wantErr: true},
+		{name: "missing dash", header: "bytes=4", wantErr: true},
+		{name: "all unsatisfiable", header: "bytes=10-", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseRange(tt.header, 10)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(httpRange{})); diff != "" {
+				t.Errorf("parseRange() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
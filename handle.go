@@ -0,0 +1,149 @@
+package httpio
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// StatusCoder is implemented by response types that set their own success status code
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// HandleOption configures Handle
+type HandleOption func(c *handleConfig)
+
+// WithDecodeOptions configures how Handle decodes the request body. A validator set with
+// WithValidator runs once the whole request has been bound, even when there is no body.
+func WithDecodeOptions(opts ...DecodeOption) HandleOption {
+	return func(c *handleConfig) {
+		c.decodeOptions = append(c.decodeOptions, opts...)
+	}
+}
+
+// WithEncoderOptions configures the Encoder used by Handle to write responses
+func WithEncoderOptions(opts ...EncoderOption) HandleOption {
+	return func(c *handleConfig) {
+		c.encoderOptions = append(c.encoderOptions, opts...)
+	}
+}
+
+// WithLogOptions configures how Handle logs errors, using the same options as Log
+func WithLogOptions(opts ...LogOption) HandleOption {
+	return func(c *handleConfig) {
+		c.logOptions = append(c.logOptions, opts...)
+	}
+}
+
+type handleConfig struct {
+	decodeOptions  []DecodeOption
+	encoderOptions []EncoderOption
+	logOptions     []LogOption
+}
+
+// Handle returns a http.HandlerFunc that binds the request into a value of type Req, calls fn,
+// and encodes the returned value of type Resp.
+//
+// Req is bound in order from the JSON request body, when one is sent, and then from the fields
+// tagged with `path` (chi URL parameters), `query` and `header`, using the same conversion rules as Param.
+// Binding failures are returned to the client as a ClientMessage. When Req is a pointer, a new value is
+// bound for each request. Handle panics if a tagged field has a type that Param cannot convert.
+//
+// Resp is written with the status code returned by its StatusCode method when it implements StatusCoder,
+// as a No Content (204) response when it is struct{}, and as an Ok (200) response otherwise.
+//
+// Errors returned by fn are written with Encoder.ClientMessage and logged following the rules of Log.
+//
+// Example usage:
+//
+//	type GetUserRequest struct {
+//		ID     int64  `path:"id"`
+//		Fields string `query:"fields"`
+//	}
+//
+//	r.Get("/users/{id}", httpio.Handle(func(ctx context.Context, req GetUserRequest) (*User, error) {
+//		return store.User(ctx, req.ID)
+//	}))
+func Handle[Req, Resp any](fn func(ctx context.Context, req Req) (Resp, error), opts ...HandleOption) http.HandlerFunc {
+	cfg := &handleConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	decodeCfg := newDecodeConfig(cfg.decodeOptions)
+	validator := decodeCfg.validator
+	decodeCfg.validator = nil
+
+	reqType := reflect.TypeFor[Req]()
+	checkBindFields(reqType, "path", "query", "header")
+
+	return Log(func(w http.ResponseWriter, r *http.Request) error {
+		e := NewEncoder(w, cfg.encoderOptions...)
+
+		var req Req
+		target := any(&req)
+		if reqType.Kind() == reflect.Pointer {
+			// bind into a new value rather than through a nil pointer
+			req = reflect.New(reqType.Elem()).Interface().(Req) //nolint:forcetypeassert // the type is Req
+			target = req
+		}
+		reqCfg := *decodeCfg
+		reqCfg.w = w
+		if err := bindRequest(r, target, &reqCfg); err != nil {
+			return e.ClientMessage(r.Context(), err)
+		}
+		if err := validate(target, validator); err != nil {
+			return e.ClientMessage(r.Context(), err)
+		}
+
+		resp, err := fn(r.Context(), req)
+		if err != nil {
+			return e.ClientMessage(r.Context(), err)
+		}
+
+		return encodeResponse(e, resp)
+	}, cfg.logOptions...)
+}
+
+// bindRequest decodes the request body into v, when one is sent, and then binds the path, query and header values
+func bindRequest(r *http.Request, v any, cfg *decodeConfig) error {
+	if r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0 {
+		if err := decodeInto(r, v, cfg); err != nil {
+			return err
+		}
+	}
+
+	if err := bindValues(v, "path", func(name string) []string {
+		if p := chi.URLParam(r, name); p != "" {
+			return []string{p}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	query := r.URL.Query()
+	if err := bindValues(v, "query", func(name string) []string { return query[name] }); err != nil {
+		return err
+	}
+
+	return bindValues(v, "header", r.Header.Values)
+}
+
+// encodeResponse writes resp with the success status code for its type
+func encodeResponse[Resp any](e *Encoder, resp Resp) error {
+	if sc, ok := any(resp).(StatusCoder); ok {
+		return e.StatusCodeWithBody(sc.StatusCode(), resp)
+	}
+
+	if reflect.TypeFor[Resp]() == reflect.TypeFor[struct{}]() {
+		e.w.Header().Del("Content-Type")
+
+		return e.StatusCodeWithBody(http.StatusNoContent, nil)
+	}
+
+	return e.Ok(resp)
+}
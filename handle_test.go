@@ -0,0 +1,260 @@
+package httpio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/errors/v5"
+)
+
+type handleRequest struct {
+	ID      int64    `path:"id"`
+	Fields  []string `query:"field"`
+	Verbose bool     `query:"verbose"`
+	Tenant  string   `header:"X-Tenant"`
+	Name    string   `json:"name"`
+}
+
+type handleResponse struct {
+	ID      int64    `json:"id"`
+	Fields  []string `json:"fields,omitempty"`
+	Verbose bool     `json:"verbose,omitempty"`
+	Tenant  string   `json:"tenant,omitempty"`
+	Name    string   `json:"name,omitempty"`
+}
+
+type createdResponse struct {
+	ID int64 `json:"id"`
+}
+
+func (createdResponse) StatusCode() int {
+	return http.StatusCreated
+}
+
+func TestHandle(t *testing.T) {
+	t.Parallel()
+
+	echo := Handle(func(_ context.Context, req handleRequest) (handleResponse, error) {
+		if req.ID == 404 {
+			return handleResponse{}, NewNotFoundMessagef("item %d not found", req.ID)
+		}
+		if req.ID == 500 {
+			return handleResponse{}, errors.New("database unavailable")
+		}
+
+		return handleResponse(req), nil
+	}, WithDecodeOptions(WithValidator(func(v any) error {
+		if v.(*handleRequest).ID <= 0 {
+			return errors.New("id must be positive")
+		}
+
+		return nil
+	})))
+
+	router := chi.NewRouter()
+	router.Get("/items/{id}", echo)
+	router.Put("/items/{id}", echo)
+	router.Post("/items", Handle(func(_ context.Context, _ struct{}) (createdResponse, error) {
+		return createdResponse{ID: 7}, nil
+	}))
+	router.Delete("/items/{id}", Handle(func(_ context.Context, _ handleRequest) (struct{}, error) {
+		return struct{}{}, nil
+	}))
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		headers    map[string]string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "path query and header",
+			method:     http.MethodGet,
+			target:     "/items/12?field=a&field=b&verbose=true",
+			headers:    map[string]string{"X-Tenant": "acme"},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"id":12,"fields":["a","b"],"verbose":true,"tenant":"acme"}`,
+		},
+		{
+			name:       "body with path override",
+			method:     http.MethodPut,
+			target:     "/items/12",
+			body:       `{"name":"widget"}`,
+			headers:    map[string]string{"Content-Type": "application/json"},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"id":12,"name":"widget"}`,
+		},
+		{
+			name:       "status coder",
+			method:     http.MethodPost,
+			target:     "/items",
+			wantStatus: http.StatusCreated,
+			wantBody:   `{"id":7}`,
+		},
+		{
+			name:       "no content",
+			method:     http.MethodDelete,
+			target:     "/items/12",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "invalid path value",
+			method:     http.MethodGet,
+			target:     "/items/abc",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"message":"param id=abc is not a valid int64. err: strconv.ParseInt: parsing \"abc\": invalid syntax"}`,
+		},
+		{
+			name:       "malformed body",
+			method:     http.MethodPut,
+			target:     "/items/12",
+			body:       `{"name":`,
+			headers:    map[string]string{"Content-Type": "application/json"},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"message":"request body contains malformed JSON at byte offset 8"}`,
+		},
+		{
+			name:       "validation failure",
+			method:     http.MethodGet,
+			target:     "/items/-1",
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   `{"message":"id must be positive"}`,
+		},
+		{
+			name:       "client message",
+			method:     http.MethodGet,
+			target:     "/items/404",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"message":"item 404 not found"}`,
+		},
+		{
+			name:       "internal error",
+			method:     http.MethodGet,
+			target:     "/items/500",
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, r)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("Handle() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if got := strings.TrimSpace(recorder.Body.String()); got != tt.wantBody {
+				t.Errorf("Handle() body = %s, want %s", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+type handleOptionalRequest struct {
+	Limit *int     `query:"limit"`
+	Tags  []*int64 `query:"tag"`
+}
+
+type handleOptionalResponse struct {
+	Limit *int     `json:"limit,omitempty"`
+	Tags  []*int64 `json:"tags,omitempty"`
+}
+
+func TestHandle_optionalFields(t *testing.T) {
+	t.Parallel()
+
+	router := chi.NewRouter()
+	router.Get("/value", Handle(func(_ context.Context, req handleOptionalRequest) (handleOptionalResponse, error) {
+		return handleOptionalResponse(req), nil
+	}))
+	router.Get("/pointer", Handle(func(_ context.Context, req *handleOptionalRequest) (handleOptionalResponse, error) {
+		return handleOptionalResponse(*req), nil
+	}))
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "pointer field", target: "/value?limit=5&tag=1&tag=2", wantStatus: http.StatusOK, wantBody: `{"limit":5,"tags":[1,2]}`},
+		{name: "missing pointer field", target: "/value", wantStatus: http.StatusOK, wantBody: `{}`},
+		{
+			name:       "invalid pointer field",
+			target:     "/value?limit=x",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"message":"param limit=x is not a valid int. err: strconv.Atoi: parsing \"x\": invalid syntax"}`,
+		},
+		{name: "pointer request", target: "/pointer?limit=5", wantStatus: http.StatusOK, wantBody: `{"limit":5}`},
+		{name: "pointer request without values", target: "/pointer", wantStatus: http.StatusOK, wantBody: `{}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.target, http.NoBody))
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("Handle() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if got := strings.TrimSpace(recorder.Body.String()); got != tt.wantBody {
+				t.Errorf("Handle() body = %s, want %s", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestHandle_unsupportedField(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		Count int32 `query:"count"`
+	}
+
+	defer func() {
+		want := "support for int32 in field request.Count has not been implemented"
+		if got := recover(); got != want {
+			t.Errorf("Handle() panic = %v, want %q", got, want)
+		}
+	}()
+
+	Handle(func(_ context.Context, _ request) (struct{}, error) {
+		return struct{}{}, nil
+	})
+}
+
+func TestHandle_bodyTooLarge(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(Handle(func(_ context.Context, req handleRequest) (handleResponse, error) {
+		return handleResponse(req), nil
+	}, WithDecodeOptions(MaxBytes(8))))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL, "application/json", strings.NewReader(`{"name":"a long name"}`))
+	if err != nil {
+		t.Fatalf("http.Post() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("Handle() status = %d, want %d", resp.StatusCode, http.StatusRequestEntityTooLarge)
+	}
+	if !resp.Close {
+		t.Errorf("Handle() did not close the connection after a body larger than MaxBytes")
+	}
+}
@@ -0,0 +1,40 @@
+//go:build httpio_debug
+
+package httperr
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cccteam/httpio"
+	"github.com/go-playground/errors/v5"
+)
+
+func TestEncoder_WithDebug_wrapped(t *testing.T) {
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	err := errors.Wrap(NewNotFoundMessage("item not found"), "store.Item()")
+	_ = httpio.NewEncoder(recorder, httpio.WithDebug()).ClientMessage(context.Background(), err)
+
+	var resp httpio.MessageResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&resp); err != nil {
+		t.Fatalf("json.Decoder.Decode() error = %v", err)
+	}
+	if resp.Debug == nil {
+		t.Fatalf("Encoder.ClientMessage() debug = nil, want the error details")
+	}
+
+	// only the stack recorded by the httperr constructor reaches the test runner
+	var recorded bool
+	for _, frame := range resp.Debug.Frames {
+		if frame.Function == "testing.tRunner" {
+			recorded = true
+		}
+	}
+	if !recorded {
+		t.Errorf("Encoder.ClientMessage() debug frames = %+v, want the stack of the httperr error", resp.Debug.Frames)
+	}
+}
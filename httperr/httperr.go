@@ -0,0 +1,586 @@
+// Package httperr provides the httpio client message constructors without go-playground/errors.
+//
+// The constructors return plain error values that wrap a httpio.ClientMessage, so they work with
+// httpio.Encoder.ClientMessage, httpio.Messages, httpio.Log and the standard library errors package.
+// Each captures the stack of its caller with runtime.Callers, which is printed with the %+v verb.
+// Nothing in this package mutates global state, but httpio registers a format function with
+// go-playground/errors when it is initialized. Build with the httpio_noerrorformat tag to prevent it.
+package httperr
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// statusClientClosedRequest is the non-standard status code used when the client closed the request
+const statusClientClosedRequest = 499
+
+// NewBadRequest creates a new empty client message with a BadRequest (400) return code
+func NewBadRequest() error {
+	return newError(http.StatusBadRequest, nil, "")
+}
+
+// NewUnauthorized creates a new empty client message with a Unauthorized (401) return code
+func NewUnauthorized() error {
+	return newError(http.StatusUnauthorized, nil, "")
+}
+
+// NewForbidden creates a new empty client message with a Forbidden (403) return code
+func NewForbidden() error {
+	return newError(http.StatusForbidden, nil, "")
+}
+
+// NewNotFound creates a new empty client message with a NotFound (404) return code
+func NewNotFound() error {
+	return newError(http.StatusNotFound, nil, "")
+}
+
+// NewMethodNotAllowed creates a new empty client message with a MethodNotAllowed (405) return code
+func NewMethodNotAllowed() error {
+	return newError(http.StatusMethodNotAllowed, nil, "")
+}
+
+// NewNotAcceptable creates a new empty client message with a NotAcceptable (406) return code
+func NewNotAcceptable() error {
+	return newError(http.StatusNotAcceptable, nil, "")
+}
+
+// NewRequestTimeout creates a new empty client message with a RequestTimeout (408) return code
+func NewRequestTimeout() error {
+	return newError(http.StatusRequestTimeout, nil, "")
+}
+
+// NewConflict creates a new empty client message with a Conflict (409) return code
+func NewConflict() error {
+	return newError(http.StatusConflict, nil, "")
+}
+
+// NewRequestEntityTooLarge creates a new empty client message with a RequestEntityTooLarge (413) return code
+func NewRequestEntityTooLarge() error {
+	return newError(http.StatusRequestEntityTooLarge, nil, "")
+}
+
+// NewUnsupportedMediaType creates a new empty client message with a UnsupportedMediaType (415) return code
+func NewUnsupportedMediaType() error {
+	return newError(http.StatusUnsupportedMediaType, nil, "")
+}
+
+// NewRequestedRangeNotSatisfiable creates a new empty client message with a RequestedRangeNotSatisfiable (416) return code
+func NewRequestedRangeNotSatisfiable() error {
+	return newError(http.StatusRequestedRangeNotSatisfiable, nil, "")
+}
+
+// NewUnprocessableEntity creates a new empty client message with a UnprocessableEntity (422) return code
+func NewUnprocessableEntity() error {
+	return newError(http.StatusUnprocessableEntity, nil, "")
+}
+
+// NewTooManyRequests creates a new empty client message with a TooManyRequests (429) return code
+func NewTooManyRequests() error {
+	return newError(http.StatusTooManyRequests, nil, "")
+}
+
+// NewClientClosedRequest creates a new empty client message with a ClientClosedRequest (499) return code
+func NewClientClosedRequest() error {
+	return newError(statusClientClosedRequest, nil, "")
+}
+
+// NewInternalServerError creates a new empty client message with a InternalServerError (500) return code
+func NewInternalServerError() error {
+	return newError(http.StatusInternalServerError, nil, "")
+}
+
+// NewNotImplemented creates a new empty client message with a NotImplemented (501) return code
+func NewNotImplemented() error {
+	return newError(http.StatusNotImplemented, nil, "")
+}
+
+// NewBadGateway creates a new empty client message with a BadGateway (502) return code
+func NewBadGateway() error {
+	return newError(http.StatusBadGateway, nil, "")
+}
+
+// NewServiceUnavailable creates a new empty client message with a ServiceUnavailable (503) return code
+func NewServiceUnavailable() error {
+	return newError(http.StatusServiceUnavailable, nil, "")
+}
+
+// NewGatewayTimeout creates a new empty client message with a GatewayTimeout (504) return code
+func NewGatewayTimeout() error {
+	return newError(http.StatusGatewayTimeout, nil, "")
+}
+
+// NewBadRequestWithError wraps an existing error while creating a new empty client message and a BadRequest (400) return code
+func NewBadRequestWithError(err error) error {
+	return newError(http.StatusBadRequest, err, "")
+}
+
+// NewUnauthorizedWithError wraps an existing error while creating a new empty client message and a Unauthorized (401) return code
+func NewUnauthorizedWithError(err error) error {
+	return newError(http.StatusUnauthorized, err, "")
+}
+
+// NewForbiddenWithError wraps an existing error while creating a new empty client message and a Forbidden (403) return code
+func NewForbiddenWithError(err error) error {
+	return newError(http.StatusForbidden, err, "")
+}
+
+// NewNotFoundWithError wraps an existing error while creating a new empty client message and a NotFound (404) return code
+func NewNotFoundWithError(err error) error {
+	return newError(http.StatusNotFound, err, "")
+}
+
+// NewMethodNotAllowedWithError wraps an existing error while creating a new empty client message and a MethodNotAllowed (405) return code
+func NewMethodNotAllowedWithError(err error) error {
+	return newError(http.StatusMethodNotAllowed, err, "")
+}
+
+// NewNotAcceptableWithError wraps an existing error while creating a new empty client message and a NotAcceptable (406) return code
+func NewNotAcceptableWithError(err error) error {
+	return newError(http.StatusNotAcceptable, err, "")
+}
+
+// NewRequestTimeoutWithError wraps an existing error while creating a new empty client message and a RequestTimeout (408) return code
+func NewRequestTimeoutWithError(err error) error {
+	return newError(http.StatusRequestTimeout, err, "")
+}
+
+// NewConflictWithError wraps an existing error while creating a new empty client message and a Conflict (409) return code
+func NewConflictWithError(err error) error {
+	return newError(http.StatusConflict, err, "")
+}
+
+// NewRequestEntityTooLargeWithError wraps an existing error while creating a new empty client message and a RequestEntityTooLarge (413) return code
+func NewRequestEntityTooLargeWithError(err error) error {
+	return newError(http.StatusRequestEntityTooLarge, err, "")
+}
+
+// NewUnsupportedMediaTypeWithError wraps an existing error while creating a new empty client message and a UnsupportedMediaType (415) return code
+func NewUnsupportedMediaTypeWithError(err error) error {
+	return newError(http.StatusUnsupportedMediaType, err, "")
+}
+
+// NewRequestedRangeNotSatisfiableWithError wraps an existing error while creating a new empty client message and a RequestedRangeNotSatisfiable (416) return code
+func NewRequestedRangeNotSatisfiableWithError(err error) error {
+	return newError(http.StatusRequestedRangeNotSatisfiable, err, "")
+}
+
+// NewUnprocessableEntityWithError wraps an existing error while creating a new empty client message and a UnprocessableEntity (422) return code
+func NewUnprocessableEntityWithError(err error) error {
+	return newError(http.StatusUnprocessableEntity, err, "")
+}
+
+// NewTooManyRequestsWithError wraps an existing error while creating a new empty client message and a TooManyRequests (429) return code
+func NewTooManyRequestsWithError(err error) error {
+	return newError(http.StatusTooManyRequests, err, "")
+}
+
+// NewClientClosedRequestWithError wraps an existing error while creating a new empty client message and a ClientClosedRequest (499) return code
+func NewClientClosedRequestWithError(err error) error {
+	return newError(statusClientClosedRequest, err, "")
+}
+
+// NewInternalServerErrorWithError wraps an existing error while creating a new empty client message and a InternalServerError (500) return code
+func NewInternalServerErrorWithError(err error) error {
+	return newError(http.StatusInternalServerError, err, "")
+}
+
+// NewNotImplementedWithError wraps an existing error while creating a new empty client message and a NotImplemented (501) return code
+func NewNotImplementedWithError(err error) error {
+	return newError(http.StatusNotImplemented, err, "")
+}
+
+// NewBadGatewayWithError wraps an existing error while creating a new empty client message and a BadGateway (502) return code
+func NewBadGatewayWithError(err error) error {
+	return newError(http.StatusBadGateway, err, "")
+}
+
+// NewServiceUnavailableWithError wraps an existing error while creating a new empty client message and a ServiceUnavailable (503) return code
+func NewServiceUnavailableWithError(err error) error {
+	return newError(http.StatusServiceUnavailable, err, "")
+}
+
+// NewGatewayTimeoutWithError wraps an existing error while creating a new empty client message and a GatewayTimeout (504) return code
+func NewGatewayTimeoutWithError(err error) error {
+	return newError(http.StatusGatewayTimeout, err, "")
+}
+
+// NewBadRequestMessage creates a new client message with a BadRequest (400) return code
+func NewBadRequestMessage(message string) error {
+	return newError(http.StatusBadRequest, nil, message)
+}
+
+// NewUnauthorizedMessage creates a new client message with a Unauthorized (401) return code
+func NewUnauthorizedMessage(message string) error {
+	return newError(http.StatusUnauthorized, nil, message)
+}
+
+// NewForbiddenMessage creates a new client message with a Forbidden (403) return code
+func NewForbiddenMessage(message string) error {
+	return newError(http.StatusForbidden, nil, message)
+}
+
+// NewNotFoundMessage creates a new client message with a NotFound (404) return code
+func NewNotFoundMessage(message string) error {
+	return newError(http.StatusNotFound, nil, message)
+}
+
+// NewMethodNotAllowedMessage creates a new client message with a MethodNotAllowed (405) return code
+func NewMethodNotAllowedMessage(message string) error {
+	return newError(http.StatusMethodNotAllowed, nil, message)
+}
+
+// NewNotAcceptableMessage creates a new client message with a NotAcceptable (406) return code
+func NewNotAcceptableMessage(message string) error {
+	return newError(http.StatusNotAcceptable, nil, message)
+}
+
+// NewRequestTimeoutMessage creates a new client message with a RequestTimeout (408) return code
+func NewRequestTimeoutMessage(message string) error {
+	return newError(http.StatusRequestTimeout, nil, message)
+}
+
+// NewConflictMessage creates a new client message with a Conflict (409) return code
+func NewConflictMessage(message string) error {
+	return newError(http.StatusConflict, nil, message)
+}
+
+// NewRequestEntityTooLargeMessage creates a new client message with a RequestEntityTooLarge (413) return code
+func NewRequestEntityTooLargeMessage(message string) error {
+	return newError(http.StatusRequestEntityTooLarge, nil, message)
+}
+
+// NewUnsupportedMediaTypeMessage creates a new client message with a UnsupportedMediaType (415) return code
+func NewUnsupportedMediaTypeMessage(message string) error {
+	return newError(http.StatusUnsupportedMediaType, nil, message)
+}
+
+// NewRequestedRangeNotSatisfiableMessage creates a new client message with a RequestedRangeNotSatisfiable (416) return code
+func NewRequestedRangeNotSatisfiableMessage(message string) error {
+	return newError(http.StatusRequestedRangeNotSatisfiable, nil, message)
+}
+
+// NewUnprocessableEntityMessage creates a new client message with a UnprocessableEntity (422) return code
+func NewUnprocessableEntityMessage(message string) error {
+	return newError(http.StatusUnprocessableEntity, nil, message)
+}
+
+// NewTooManyRequestsMessage creates a new client message with a TooManyRequests (429) return code
+func NewTooManyRequestsMessage(message string) error {
+	return newError(http.StatusTooManyRequests, nil, message)
+}
+
+// NewClientClosedRequestMessage creates a new client message with a ClientClosedRequest (499) return code
+func NewClientClosedRequestMessage(message string) error {
+	return newError(statusClientClosedRequest, nil, message)
+}
+
+// NewInternalServerErrorMessage creates a new client message with a InternalServerError (500) return code
+func NewInternalServerErrorMessage(message string) error {
+	return newError(http.StatusInternalServerError, nil, message)
+}
+
+// NewNotImplementedMessage creates a new client message with a NotImplemented (501) return code
+func NewNotImplementedMessage(message string) error {
+	return newError(http.StatusNotImplemented, nil, message)
+}
+
+// NewBadGatewayMessage creates a new client message with a BadGateway (502) return code
+func NewBadGatewayMessage(message string) error {
+	return newError(http.StatusBadGateway, nil, message)
+}
+
+// NewServiceUnavailableMessage creates a new client message with a ServiceUnavailable (503) return code
+func NewServiceUnavailableMessage(message string) error {
+	return newError(http.StatusServiceUnavailable, nil, message)
+}
+
+// NewGatewayTimeoutMessage creates a new client message with a GatewayTimeout (504) return code
+func NewGatewayTimeoutMessage(message string) error {
+	return newError(http.StatusGatewayTimeout, nil, message)
+}
+
+// NewBadRequestMessagef creates a new client message with a BadRequest (400) return code
+func NewBadRequestMessagef(format string, a ...any) error {
+	return newError(http.StatusBadRequest, nil, fmt.Sprintf(format, a...))
+}
+
+// NewUnauthorizedMessagef creates a new client message with a Unauthorized (401) return code
+func NewUnauthorizedMessagef(format string, a ...any) error {
+	return newError(http.StatusUnauthorized, nil, fmt.Sprintf(format, a...))
+}
+
+// NewForbiddenMessagef creates a new client message with a Forbidden (403) return code
+func NewForbiddenMessagef(format string, a ...any) error {
+	return newError(http.StatusForbidden, nil, fmt.Sprintf(format, a...))
+}
+
+// NewNotFoundMessagef creates a new client message with a NotFound (404) return code
+func NewNotFoundMessagef(format string, a ...any) error {
+	return newError(http.StatusNotFound, nil, fmt.Sprintf(format, a...))
+}
+
+// NewMethodNotAllowedMessagef creates a new client message with a MethodNotAllowed (405) return code
+func NewMethodNotAllowedMessagef(format string, a ...any) error {
+	return newError(http.StatusMethodNotAllowed, nil, fmt.Sprintf(format, a...))
+}
+
+// NewNotAcceptableMessagef creates a new client message with a NotAcceptable (406) return code
+func NewNotAcceptableMessagef(format string, a ...any) error {
+	return newError(http.StatusNotAcceptable, nil, fmt.Sprintf(format, a...))
+}
+
+// NewRequestTimeoutMessagef creates a new client message with a RequestTimeout (408) return code
+func NewRequestTimeoutMessagef(format string, a ...any) error {
+	return newError(http.StatusRequestTimeout, nil, fmt.Sprintf(format, a...))
+}
+
+// NewConflictMessagef creates a new client message with a Conflict (409) return code
+func NewConflictMessagef(format string, a ...any) error {
+	return newError(http.StatusConflict, nil, fmt.Sprintf(format, a...))
+}
+
+// NewRequestEntityTooLargeMessagef creates a new client message with a RequestEntityTooLarge (413) return code
+func NewRequestEntityTooLargeMessagef(format string, a ...any) error {
+	return newError(http.StatusRequestEntityTooLarge, nil, fmt.Sprintf(format, a...))
+}
+
+// NewUnsupportedMediaTypeMessagef creates a new client message with a UnsupportedMediaType (415) return code
+func NewUnsupportedMediaTypeMessagef(format string, a ...any) error {
+	return newError(http.StatusUnsupportedMediaType, nil, fmt.Sprintf(format, a...))
+}
+
+// NewRequestedRangeNotSatisfiableMessagef creates a new client message with a RequestedRangeNotSatisfiable (416) return code
+func NewRequestedRangeNotSatisfiableMessagef(format string, a ...any) error {
+	return newError(http.StatusRequestedRangeNotSatisfiable, nil, fmt.Sprintf(format, a...))
+}
+
+// NewUnprocessableEntityMessagef creates a new client message with a UnprocessableEntity (422) return code
+func NewUnprocessableEntityMessagef(format string, a ...any) error {
+	return newError(http.StatusUnprocessableEntity, nil, fmt.Sprintf(format, a...))
+}
+
+// NewTooManyRequestsMessagef creates a new client message with a TooManyRequests (429) return code
+func NewTooManyRequestsMessagef(format string, a ...any) error {
+	return newError(http.StatusTooManyRequests, nil, fmt.Sprintf(format, a...))
+}
+
+// NewClientClosedRequestMessagef creates a new client message with a ClientClosedRequest (499) return code
+func NewClientClosedRequestMessagef(format string, a ...any) error {
+	return newError(statusClientClosedRequest, nil, fmt.Sprintf(format, a...))
+}
+
+// NewInternalServerErrorMessagef creates a new client message with a InternalServerError (500) return code
+func NewInternalServerErrorMessagef(format string, a ...any) error {
+	return newError(http.StatusInternalServerError, nil, fmt.Sprintf(format, a...))
+}
+
+// NewNotImplementedMessagef creates a new client message with a NotImplemented (501) return code
+func NewNotImplementedMessagef(format string, a ...any) error {
+	return newError(http.StatusNotImplemented, nil, fmt.Sprintf(format, a...))
+}
+
+// NewBadGatewayMessagef creates a new client message with a BadGateway (502) return code
+func NewBadGatewayMessagef(format string, a ...any) error {
+	return newError(http.StatusBadGateway, nil, fmt.Sprintf(format, a...))
+}
+
+// NewServiceUnavailableMessagef creates a new client message with a ServiceUnavailable (503) return code
+func NewServiceUnavailableMessagef(format string, a ...any) error {
+	return newError(http.StatusServiceUnavailable, nil, fmt.Sprintf(format, a...))
+}
+
+// NewGatewayTimeoutMessagef creates a new client message with a GatewayTimeout (504) return code
+func NewGatewayTimeoutMessagef(format string, a ...any) error {
+	return newError(http.StatusGatewayTimeout, nil, fmt.Sprintf(format, a...))
+}
+
+// NewBadRequestMessageWithError wraps an existing error while creating a new client message with a BadRequest (400) return code
+func NewBadRequestMessageWithError(err error, message string) error {
+	return newError(http.StatusBadRequest, err, message)
+}
+
+// NewUnauthorizedMessageWithError wraps an existing error while creating a new client message with a Unauthorized (401) return code
+func NewUnauthorizedMessageWithError(err error, message string) error {
+	return newError(http.StatusUnauthorized, err, message)
+}
+
+// NewForbiddenMessageWithError wraps an existing error while creating a new client message with a Forbidden (403) return code
+func NewForbiddenMessageWithError(err error, message string) error {
+	return newError(http.StatusForbidden, err, message)
+}
+
+// NewNotFoundMessageWithError wraps an existing error while creating a new client message with a NotFound (404) return code
+func NewNotFoundMessageWithError(err error, message string) error {
+	return newError(http.StatusNotFound, err, message)
+}
+
+// NewMethodNotAllowedMessageWithError wraps an existing error while creating a new client message with a MethodNotAllowed (405) return code
+func NewMethodNotAllowedMessageWithError(err error, message string) error {
+	return newError(http.StatusMethodNotAllowed, err, message)
+}
+
+// NewNotAcceptableMessageWithError wraps an existing error while creating a new client message with a NotAcceptable (406) return code
+func NewNotAcceptableMessageWithError(err error, message string) error {
+	return newError(http.StatusNotAcceptable, err, message)
+}
+
+// NewRequestTimeoutMessageWithError wraps an existing error while creating a new client message with a RequestTimeout (408) return code
+func NewRequestTimeoutMessageWithError(err error, message string) error {
+	return newError(http.StatusRequestTimeout, err, message)
+}
+
+// NewConflictMessageWithError wraps an existing error while creating a new client message with a Conflict (409) return code
+func NewConflictMessageWithError(err error, message string) error {
+	return newError(http.StatusConflict, err, message)
+}
+
+// NewRequestEntityTooLargeMessageWithError wraps an existing error while creating a new client message with a RequestEntityTooLarge (413) return code
+func NewRequestEntityTooLargeMessageWithError(err error, message string) error {
+	return newError(http.StatusRequestEntityTooLarge, err, message)
+}
+
+// NewUnsupportedMediaTypeMessageWithError wraps an existing error while creating a new client message with a UnsupportedMediaType (415) return code
+func NewUnsupportedMediaTypeMessageWithError(err error, message string) error {
+	return newError(http.StatusUnsupportedMediaType, err, message)
+}
+
+// NewRequestedRangeNotSatisfiableMessageWithError wraps an existing error while creating a new client message with a RequestedRangeNotSatisfiable (416) return code
+func NewRequestedRangeNotSatisfiableMessageWithError(err error, message string) error {
+	return newError(http.StatusRequestedRangeNotSatisfiable, err, message)
+}
+
+// NewUnprocessableEntityMessageWithError wraps an existing error while creating a new client message with a UnprocessableEntity (422) return code
+func NewUnprocessableEntityMessageWithError(err error, message string) error {
+	return newError(http.StatusUnprocessableEntity, err, message)
+}
+
+// NewTooManyRequestsMessageWithError wraps an existing error while creating a new client message with a TooManyRequests (429) return code
+func NewTooManyRequestsMessageWithError(err error, message string) error {
+	return newError(http.StatusTooManyRequests, err, message)
+}
+
+// NewClientClosedRequestMessageWithError wraps an existing error while creating a new client message with a ClientClosedRequest (499) return code
+func NewClientClosedRequestMessageWithError(err error, message string) error {
+	return newError(statusClientClosedRequest, err, message)
+}
+
+// NewInternalServerErrorMessageWithError wraps an existing error while creating a new client message with a InternalServerError (500) return code
+func NewInternalServerErrorMessageWithError(err error, message string) error {
+	return newError(http.StatusInternalServerError, err, message)
+}
+
+// NewNotImplementedMessageWithError wraps an existing error while creating a new client message with a NotImplemented (501) return code
+func NewNotImplementedMessageWithError(err error, message string) error {
+	return newError(http.StatusNotImplemented, err, message)
+}
+
+// NewBadGatewayMessageWithError wraps an existing error while creating a new client message with a BadGateway (502) return code
+func NewBadGatewayMessageWithError(err error, message string) error {
+	return newError(http.StatusBadGateway, err, message)
+}
+
+// NewServiceUnavailableMessageWithError wraps an existing error while creating a new client message with a ServiceUnavailable (503) return code
+func NewServiceUnavailableMessageWithError(err error, message string) error {
+	return newError(http.StatusServiceUnavailable, err, message)
+}
+
+// NewGatewayTimeoutMessageWithError wraps an existing error while creating a new client message with a GatewayTimeout (504) return code
+func NewGatewayTimeoutMessageWithError(err error, message string) error {
+	return newError(http.StatusGatewayTimeout, err, message)
+}
+
+// NewBadRequestMessageWithErrorf wraps an existing error while creating a new client message with a BadRequest (400) return code
+func NewBadRequestMessageWithErrorf(err error, format string, a ...any) error {
+	return newError(http.StatusBadRequest, err, fmt.Sprintf(format, a...))
+}
+
+// NewUnauthorizedMessageWithErrorf wraps an existing error while creating a new client message with a Unauthorized (401) return code
+func NewUnauthorizedMessageWithErrorf(err error, format string, a ...any) error {
+	return newError(http.StatusUnauthorized, err, fmt.Sprintf(format, a...))
+}
+
+// NewForbiddenMessageWithErrorf wraps an existing error while creating a new client message with a Forbidden (403) return code
+func NewForbiddenMessageWithErrorf(err error, format string, a ...any) error {
+	return newError(http.StatusForbidden, err, fmt.Sprintf(format, a...))
+}
+
+// NewNotFoundMessageWithErrorf wraps an existing error while creating a new client message with a NotFound (404) return code
+func NewNotFoundMessageWithErrorf(err error, format string, a ...any) error {
+	return newError(http.StatusNotFound, err, fmt.Sprintf(format, a...))
+}
+
+// NewMethodNotAllowedMessageWithErrorf wraps an existing error while creating a new client message with a MethodNotAllowed (405) return code
+func NewMethodNotAllowedMessageWithErrorf(err error, format string, a ...any) error {
+	return newError(http.StatusMethodNotAllowed, err, fmt.Sprintf(format, a...))
+}
+
+// NewNotAcceptableMessageWithErrorf wraps an existing error while creating a new client message with a NotAcceptable (406) return code
+func NewNotAcceptableMessageWithErrorf(err error, format string, a ...any) error {
+	return newError(http.StatusNotAcceptable, err, fmt.Sprintf(format, a...))
+}
+
+// NewRequestTimeoutMessageWithErrorf wraps an existing error while creating a new client message with a RequestTimeout (408) return code
+func NewRequestTimeoutMessageWithErrorf(err error, format string, a ...any) error {
+	return newError(http.StatusRequestTimeout, err, fmt.Sprintf(format, a...))
+}
+
+// NewConflictMessageWithErrorf wraps an existing error while creating a new client message with a Conflict (409) return code
+func NewConflictMessageWithErrorf(err error, format string, a ...any) error {
+	return newError(http.StatusConflict, err, fmt.Sprintf(format, a...))
+}
+
+// NewRequestEntityTooLargeMessageWithErrorf wraps an existing error while creating a new client message with a RequestEntityTooLarge (413) return code
+func NewRequestEntityTooLargeMessageWithErrorf(err error, format string, a ...any) error {
+	return newError(http.StatusRequestEntityTooLarge, err, fmt.Sprintf(format, a...))
+}
+
+// NewUnsupportedMediaTypeMessageWithErrorf wraps an existing error while creating a new client message with a UnsupportedMediaType (415) return code
+func NewUnsupportedMediaTypeMessageWithErrorf(err error, format string, a ...any) error {
+	return newError(http.StatusUnsupportedMediaType, err, fmt.Sprintf(format, a...))
+}
+
+// NewRequestedRangeNotSatisfiableMessageWithErrorf wraps an existing error while creating a new client message with a RequestedRangeNotSatisfiable (416) return code
+func NewRequestedRangeNotSatisfiableMessageWithErrorf(err error, format string, a ...any) error {
+	return newError(http.StatusRequestedRangeNotSatisfiable, err, fmt.Sprintf(format, a...))
+}
+
+// NewUnprocessableEntityMessageWithErrorf wraps an existing error while creating a new client message with a UnprocessableEntity (422) return code
+func NewUnprocessableEntityMessageWithErrorf(err error, format string, a ...any) error {
+	return newError(http.StatusUnprocessableEntity, err, fmt.Sprintf(format, a...))
+}
+
+// NewTooManyRequestsMessageWithErrorf wraps an existing error while creating a new client message with a TooManyRequests (429) return code
+func NewTooManyRequestsMessageWithErrorf(err error, format string, a ...any) error {
+	return newError(http.StatusTooManyRequests, err, fmt.Sprintf(format, a...))
+}
+
+// NewClientClosedRequestMessageWithErrorf wraps an existing error while creating a new client message with a ClientClosedRequest (499) return code
+func NewClientClosedRequestMessageWithErrorf(err error, format string, a ...any) error {
+	return newError(statusClientClosedRequest, err, fmt.Sprintf(format, a...))
+}
+
+// NewInternalServerErrorMessageWithErrorf wraps an existing error while creating a new client message with a InternalServerError (500) return code
+func NewInternalServerErrorMessageWithErrorf(err error, format string, a ...any) error {
+	return newError(http.StatusInternalServerError, err, fmt.Sprintf(format, a...))
+}
+
+// NewNotImplementedMessageWithErrorf wraps an existing error while creating a new client message with a NotImplemented (501) return code
+func NewNotImplementedMessageWithErrorf(err error, format string, a ...any) error {
+	return newError(http.StatusNotImplemented, err, fmt.Sprintf(format, a...))
+}
+
+// NewBadGatewayMessageWithErrorf wraps an existing error while creating a new client message with a BadGateway (502) return code
+func NewBadGatewayMessageWithErrorf(err error, format string, a ...any) error {
+	return newError(http.StatusBadGateway, err, fmt.Sprintf(format, a...))
+}
+
+// NewServiceUnavailableMessageWithErrorf wraps an existing error while creating a new client message with a ServiceUnavailable (503) return code
+func NewServiceUnavailableMessageWithErrorf(err error, format string, a ...any) error {
+	return newError(http.StatusServiceUnavailable, err, fmt.Sprintf(format, a...))
+}
+
+// NewGatewayTimeoutMessageWithErrorf wraps an existing error while creating a new client message with a GatewayTimeout (504) return code
+func NewGatewayTimeoutMessageWithErrorf(err error, format string, a ...any) error {
+	return newError(http.StatusGatewayTimeout, err, fmt.Sprintf(format, a...))
+}
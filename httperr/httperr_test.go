@@ -0,0 +1,120 @@
+package httperr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cccteam/httpio"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConstructors(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("database unavailable")
+
+	tests := []struct {
+		name         string
+		err          error
+		wantStatus   int
+		wantSentinel error
+		wantMessages []string
+		wantCause    bool
+	}{
+		{name: "empty", err: NewNotFound(), wantStatus: http.StatusNotFound, wantSentinel: httpio.ErrNotFound, wantMessages: []string{""}},
+		{name: "with error", err: NewServiceUnavailableWithError(cause), wantStatus: http.StatusServiceUnavailable, wantSentinel: httpio.ErrServiceUnavailable, wantMessages: []string{""}, wantCause: true},
+		{name: "message", err: NewConflictMessage("item exists"), wantStatus: http.StatusConflict, wantSentinel: httpio.ErrConflict, wantMessages: []string{"item exists"}},
+		{name: "messagef", err: NewBadRequestMessagef("invalid id %d", 7), wantStatus: http.StatusBadRequest, wantSentinel: httpio.ErrBadRequest, wantMessages: []string{"invalid id 7"}},
+		{name: "message with error", err: NewClientClosedRequestMessageWithError(cause, "closed"), wantStatus: 499, wantSentinel: httpio.ErrClientClosedRequest, wantMessages: []string{"closed"}, wantCause: true},
+		{name: "message with errorf", err: NewGatewayTimeoutMessageWithErrorf(cause, "after %ds", 30), wantStatus: http.StatusGatewayTimeout, wantSentinel: httpio.ErrGatewayTimeout, wantMessages: []string{"after 30s"}, wantCause: true},
+		{name: "nested", err: NewBadGatewayMessageWithError(NewNotFoundMessage("upstream item"), "upstream failed"), wantStatus: http.StatusBadGateway, wantSentinel: httpio.ErrBadGateway, wantMessages: []string{"upstream failed", "upstream item"}},
+		{name: "wrapped with fmt", err: fmt.Errorf("lookup: %w", NewRequestedRangeNotSatisfiable()), wantStatus: http.StatusRequestedRangeNotSatisfiable, wantSentinel: httpio.ErrRequestedRangeNotSatisfiable, wantMessages: []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := httpio.StatusCode(tt.err); got != tt.wantStatus {
+				t.Errorf("httpio.StatusCode() = %d, want %d", got, tt.wantStatus)
+			}
+			if !errors.Is(tt.err, tt.wantSentinel) {
+				t.Errorf("errors.Is(%v) = false, want true", tt.wantSentinel)
+			}
+			if diff := cmp.Diff(tt.wantMessages, httpio.Messages(tt.err)); diff != "" {
+				t.Errorf("httpio.Messages() mismatch (-want +got):\n%s", diff)
+			}
+			if got := httpio.CauseIsError(tt.err); got != tt.wantCause {
+				t.Errorf("httpio.CauseIsError() = %v, want %v", got, tt.wantCause)
+			}
+			if tt.wantCause && !errors.Is(tt.err, cause) {
+				t.Errorf("errors.Is(cause) = false, want true")
+			}
+		})
+	}
+}
+
+func TestEncoder_ClientMessage(t *testing.T) {
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	err := NewUnprocessableEntityMessage("name is required")
+	if got := httpio.NewEncoder(recorder).ClientMessage(context.Background(), err); !errors.Is(got, err) {
+		t.Errorf("Encoder.ClientMessage() error = %v, want %v", got, err)
+	}
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Encoder.ClientMessage() status = %d, want %d", recorder.Code, http.StatusUnprocessableEntity)
+	}
+	if got, want := strings.TrimSpace(recorder.Body.String()), `{"message":"name is required"}`; got != want {
+		t.Errorf("Encoder.ClientMessage() body = %s, want %s", got, want)
+	}
+}
+
+func TestLog(t *testing.T) {
+	t.Parallel()
+
+	handler := httpio.Log(func(_ http.ResponseWriter, _ *http.Request) error {
+		return NewForbiddenMessage("not allowed")
+	})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	if recorder.Code != http.StatusForbidden {
+		t.Errorf("Log() status = %d, want %d", recorder.Code, http.StatusForbidden)
+	}
+	body, _ := io.ReadAll(recorder.Body)
+	if got, want := strings.TrimSpace(string(body)), `{"message":"not allowed"}`; got != want {
+		t.Errorf("Log() body = %s, want %s", got, want)
+	}
+}
+
+func TestStackTrace(t *testing.T) {
+	t.Parallel()
+
+	err := NewNotFoundMessage("item not found")
+
+	var serr *stackError
+	if !errors.As(err, &serr) {
+		t.Fatalf("errors.As(*stackError) = false, want true")
+	}
+	stack := serr.StackTrace()
+	if len(stack) == 0 {
+		t.Fatalf("StackTrace() is empty")
+	}
+	if got, want := stack[0].Function, "github.com/cccteam/httpio/httperr.TestStackTrace"; got != want {
+		t.Errorf("StackTrace()[0].Function = %q, want %q", got, want)
+	}
+
+	if got, want := fmt.Sprintf("%v", err), `Client Message:"item not found"`; got != want {
+		t.Errorf("Sprintf(%%v) = %q, want %q", got, want)
+	}
+	if got := fmt.Sprintf("%+v", err); !strings.Contains(got, "httperr_test.go:") {
+		t.Errorf("Sprintf(%%+v) = %q, want it to contain the caller's location", got)
+	}
+}
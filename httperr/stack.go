@@ -0,0 +1,78 @@
+package httperr
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+
+	_ "github.com/cccteam/httpio" // sets clientmsg.New when it is initialized
+	"github.com/cccteam/httpio/internal/clientmsg"
+)
+
+// maxStackDepth is the maximum number of frames captured for an error
+const maxStackDepth = 32
+
+// stackError wraps a ClientMessage with the stack of the caller that created it
+type stackError struct {
+	err error
+	pcs []uintptr
+}
+
+// newError creates a ClientMessage for statusCode and captures the stack of the
+// caller of the exported constructor
+func newError(statusCode int, err error, message string) error {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(3, pcs)
+
+	return &stackError{
+		err: clientmsg.New(statusCode, err, message),
+		pcs: pcs[:n],
+	}
+}
+
+// Error returns the error message
+func (e *stackError) Error() string {
+	return e.err.Error()
+}
+
+func (e *stackError) Unwrap() error {
+	return e.err
+}
+
+// StackTrace returns the frames of the stack captured when the error was created
+func (e *stackError) StackTrace() []runtime.Frame {
+	frames := runtime.CallersFrames(e.pcs)
+
+	stack := make([]runtime.Frame, 0, len(e.pcs))
+	for {
+		frame, more := frames.Next()
+		if frame.PC != 0 {
+			stack = append(stack, frame)
+		}
+		if !more {
+			break
+		}
+	}
+
+	return stack
+}
+
+// Format implements fmt.Formatter. The %+v verb prints the error message followed by the captured stack.
+func (e *stackError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = io.WriteString(s, e.Error())
+			for _, frame := range e.StackTrace() {
+				_, _ = fmt.Fprintf(s, "\n%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+			}
+
+			return
+		}
+		_, _ = io.WriteString(s, e.Error())
+	case 's':
+		_, _ = io.WriteString(s, e.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.Error())
+	}
+}
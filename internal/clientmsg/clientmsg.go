@@ -0,0 +1,6 @@
+// Package clientmsg shares the httpio client message constructor with the other packages of this module.
+package clientmsg
+
+// New creates a *httpio.ClientMessage with the return code for statusCode, like httpio.NewClientMessage,
+// but returns the ClientMessage itself rather than an errors.Chain. It is set when httpio is initialized.
+var New func(statusCode int, err error, message string) error //nolint:gochecknoglobals // set by httpio
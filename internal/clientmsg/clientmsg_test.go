@@ -0,0 +1,50 @@
+package clientmsg_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/cccteam/httpio"
+	"github.com/cccteam/httpio/internal/clientmsg"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("database unavailable")
+
+	tests := []struct {
+		name         string
+		statusCode   int
+		err          error
+		message      string
+		wantStatus   int
+		wantMessages []string
+	}{
+		{name: "message", statusCode: http.StatusNotFound, message: "item not found", wantStatus: http.StatusNotFound, wantMessages: []string{"item not found"}},
+		{name: "with error", statusCode: http.StatusServiceUnavailable, err: cause, wantStatus: http.StatusServiceUnavailable, wantMessages: []string{""}},
+		{name: "nested", statusCode: http.StatusBadGateway, err: httpio.NewNotFoundMessage("upstream item"), message: "upstream failed", wantStatus: http.StatusBadGateway, wantMessages: []string{"upstream failed", "upstream item"}},
+		{name: "unknown status", statusCode: 418, message: "teapot", wantStatus: http.StatusBadRequest, wantMessages: []string{"teapot"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := clientmsg.New(tt.statusCode, tt.err, tt.message)
+			if _, ok := err.(*httpio.ClientMessage); !ok { //nolint:errorlint // clientmsg.New returns the ClientMessage itself
+				t.Fatalf("clientmsg.New() = %T, want *httpio.ClientMessage", err)
+			}
+			if got := httpio.StatusCode(err); got != tt.wantStatus {
+				t.Errorf("httpio.StatusCode() = %d, want %d", got, tt.wantStatus)
+			}
+			if diff := cmp.Diff(tt.wantMessages, httpio.Messages(err)); diff != "" {
+				t.Errorf("httpio.Messages() mismatch (-want +got):\n%s", diff)
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("errors.Is() = false, want true")
+			}
+		})
+	}
+}
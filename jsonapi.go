@@ -0,0 +1,590 @@
+package httpio
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/errors/v5"
+)
+
+// JSONAPIResource is a JSON:API resource object
+type JSONAPIResource struct {
+	Type          string                          `json:"type"`
+	ID            string                          `json:"id"`
+	Attributes    map[string]any                  `json:"attributes,omitempty"`
+	Relationships map[string]*JSONAPIRelationship `json:"relationships,omitempty"`
+}
+
+// JSONAPIRelationship is a JSON:API relationship object
+type JSONAPIRelationship struct {
+	// Data is a *JSONAPIResourceIdentifier for a to-one relationship, nil when it is empty,
+	// or a []*JSONAPIResourceIdentifier for a to-many relationship
+	Data any `json:"data"`
+}
+
+// JSONAPIResourceIdentifier identifies a JSON:API resource
+type JSONAPIResourceIdentifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// JSONAPIErrorSource is the source of a JSON:API error object
+type JSONAPIErrorSource struct {
+	// Pointer is the JSON Pointer (RFC 6901) to the value in the request document that caused the error
+	Pointer string `json:"pointer,omitempty"`
+	// Parameter is the query parameter that caused the error
+	Parameter string `json:"parameter,omitempty"`
+}
+
+// SourcePointer records the JSON Pointer (RFC 6901) to the value in the request document that caused err.
+// JSONAPIEnvelope writes it as the source of the error object for the ClientMessage that err is or wraps,
+// or else for the ClientMessage that err is the cause of. The field of a *json.UnmarshalTypeError is
+// used as the pointer without calling SourcePointer.
+//
+// Example usage:
+//
+//	return httpio.SourcePointer(httpio.NewUnprocessableEntityMessage("title is required"), "/data/attributes/title")
+func SourcePointer(err error, pointer string) error {
+	return &sourceError{err: err, source: JSONAPIErrorSource{Pointer: pointer}}
+}
+
+// SourceParameter records the query parameter that caused err, which JSONAPIEnvelope writes as the source
+// of the error object the same way as SourcePointer
+func SourceParameter(err error, parameter string) error {
+	return &sourceError{err: err, source: JSONAPIErrorSource{Parameter: parameter}}
+}
+
+// sourceError records the source of an error
+type sourceError struct {
+	err    error
+	source JSONAPIErrorSource
+}
+
+// Error returns the error message
+func (e *sourceError) Error() string {
+	return e.err.Error()
+}
+
+func (e *sourceError) Unwrap() error {
+	return e.err
+}
+
+// jsonAPIMessageSource is the message and source of a ClientMessage
+type jsonAPIMessageSource struct {
+	message string
+	source  *JSONAPIErrorSource
+}
+
+// jsonAPIErrorSources returns the message and source of each ClientMessage in the chain of err, outermost first
+func jsonAPIErrorSources(err error) []jsonAPIMessageSource {
+	var sources []jsonAPIMessageSource
+	var pending *JSONAPIErrorSource
+	for err != nil {
+		var source *JSONAPIErrorSource
+		switch e := err.(type) { //nolint:errorlint // each error in the chain is inspected in turn
+		case errors.Chain:
+			// only the first link of a chain holds an error
+			err = e[0].Err
+
+			continue
+		case *ClientMessage:
+			sources = append(sources, jsonAPIMessageSource{message: e.clientMessage, source: pending})
+			pending = nil
+		case *sourceError:
+			source = &e.source
+		case *json.UnmarshalTypeError:
+			if e.Field != "" {
+				source = &JSONAPIErrorSource{Pointer: "/" + strings.ReplaceAll(e.Field, ".", "/")}
+			}
+		}
+		if source != nil && pending == nil {
+			pending = source
+		}
+		err = stderrors.Unwrap(err)
+	}
+
+	// a source that is not followed by a ClientMessage belongs to the one it is the cause of
+	if pending != nil && len(sources) > 0 && sources[len(sources)-1].source == nil {
+		sources[len(sources)-1].source = pending
+	}
+
+	return sources
+}
+
+// nestedJSONAPISources filters sources the same way as nestedMessages, so they line up with the nested messages
+func nestedJSONAPISources(sources []jsonAPIMessageSource) []jsonAPIMessageSource {
+	var nested []jsonAPIMessageSource
+	seen := make(map[string]struct{})
+	for _, s := range sources {
+		if _, ok := seen[s.message]; ok || s.message == "" {
+			continue
+		}
+		seen[s.message] = struct{}{}
+		nested = append(nested, s)
+	}
+
+	return nested
+}
+
+// JSONAPI writes v as a JSON:API document with the application/vnd.api+json content type. v is a struct,
+// a pointer to a struct, or a slice of either, whose fields are tagged with:
+//
+//	jsonapi:"primary,<type>"              the resource ID and type
+//	jsonapi:"attr,<name>[,omitempty]"     an attribute
+//	jsonapi:"relation,<name>[,omitempty]" a relationship to a struct, pointer to a struct, or slice of either
+//
+// Related resources are written to the included member: all of them, or only the relationship paths
+// listed in the include query parameter of r, such as include=author,comments.author. Sparse fieldsets
+// are read from the fields[type] query parameters of r, such as fields[articles]=title,author. Unknown
+// include paths and fields are written as a BadRequest (400) with the parameter as the source.
+//
+// Errors are written as JSON:API error objects, even when the Encoder was not created WithEnvelope(JSONAPIEnvelope()).
+//
+// Example usage:
+//
+//	type Article struct {
+//		ID     int64   `jsonapi:"primary,articles"`
+//		Title  string  `jsonapi:"attr,title"`
+//		Author *Person `jsonapi:"relation,author"`
+//	}
+//
+//	return httpio.NewEncoder(w).JSONAPI(r, http.StatusOK, article)
+func (e *Encoder) JSONAPI(r *http.Request, statusCode int, v any, opts ...ResponseOption) error {
+	if e.envelope == nil {
+		e.envelope = jsonAPIEnvelope{}
+	}
+	e.w.Header().Set("Content-Type", JSONAPIContentType)
+
+	doc, err := newJSONAPIDocument(r, v)
+	if err != nil {
+		return e.ClientMessage(r.Context(), err)
+	}
+
+	o := e.applyResponseOptions(opts)
+	doc.Meta = o.meta
+	e.w.WriteHeader(statusCode)
+
+	return e.encode(doc, 2)
+}
+
+// newJSONAPIDocument builds the document for v using the include and fields[type] query parameters of r
+func newJSONAPIDocument(r *http.Request, v any) (*JSONAPIDocument, error) {
+	if v == nil {
+		return &JSONAPIDocument{Data: jsonAPINull}, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	t := rv.Type()
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	typ, err := jsonAPITypeOf(t)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &jsonAPIMarshaler{
+		seen: make(map[JSONAPIResourceIdentifier]struct{}),
+	}
+	if err := m.parseQuery(r, typ); err != nil {
+		return nil, err
+	}
+
+	data, err := m.data(rv)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JSONAPIDocument{Data: data, Included: m.included}, nil
+}
+
+// jsonAPINull is written as the primary data of an empty document
+var jsonAPINull = json.RawMessage("null") //nolint:gochecknoglobals // constant value
+
+// jsonAPIField is a tagged field of a resource struct
+type jsonAPIField struct {
+	index     int
+	name      string
+	omitEmpty bool
+}
+
+// jsonAPIType describes a resource struct
+type jsonAPIType struct {
+	t         reflect.Type
+	name      string
+	id        int
+	attrs     []jsonAPIField
+	relations []jsonAPIField
+}
+
+// jsonAPITypeOf describes the resource struct t
+func jsonAPITypeOf(t reflect.Type) (*jsonAPIType, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, errors.Newf("jsonAPITypeOf: resource must be a struct, got %s", t)
+	}
+
+	typ := &jsonAPIType{t: t, id: -1}
+	for i := range t.NumField() {
+		tag, ok := t.Field(i).Tag.Lookup("jsonapi")
+		if !ok || !t.Field(i).IsExported() {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		if len(parts) < 2 || parts[1] == "" {
+			return nil, errors.Newf("jsonAPITypeOf: invalid jsonapi tag %q on %s.%s", tag, t, t.Field(i).Name)
+		}
+		field := jsonAPIField{index: i, name: parts[1], omitEmpty: len(parts) > 2 && parts[2] == "omitempty"}
+
+		switch parts[0] {
+		case "primary":
+			typ.id, typ.name = i, parts[1]
+		case "attr":
+			typ.attrs = append(typ.attrs, field)
+		case "relation":
+			typ.relations = append(typ.relations, field)
+		default:
+			return nil, errors.Newf("jsonAPITypeOf: invalid jsonapi tag %q on %s.%s", tag, t, t.Field(i).Name)
+		}
+	}
+	if typ.id < 0 {
+		return nil, errors.Newf("jsonAPITypeOf: resource %s has no jsonapi:\"primary,<type>\" field", t)
+	}
+
+	return typ, nil
+}
+
+// relation returns the relationship named name and the resource type it refers to
+func (typ *jsonAPIType) relation(name string) (*jsonAPIType, bool, error) {
+	for _, rel := range typ.relations {
+		if rel.name != name {
+			continue
+		}
+		t := typ.t.Field(rel.index).Type
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			t = t.Elem()
+		}
+		relType, err := jsonAPITypeOf(t)
+
+		return relType, true, err
+	}
+
+	return nil, false, nil
+}
+
+// hasField reports whether typ has an attribute or relationship named name
+func (typ *jsonAPIType) hasField(name string) bool {
+	for _, f := range typ.attrs {
+		if f.name == name {
+			return true
+		}
+	}
+	for _, f := range typ.relations {
+		if f.name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// jsonAPIMarshaler builds the resource objects of a document
+type jsonAPIMarshaler struct {
+	// fields holds the sparse fieldset of each type, if requested
+	fields map[string]map[string]struct{}
+	// include holds the relationship paths to include, and their parents, or nil to include all
+	include map[string]struct{}
+	// included holds the related resources
+	included []*JSONAPIResource
+	// seen holds the resources already in the document
+	seen map[JSONAPIResourceIdentifier]struct{}
+}
+
+// parseQuery reads the include and fields[type] query parameters, validating them against the primary type
+func (m *jsonAPIMarshaler) parseQuery(r *http.Request, typ *jsonAPIType) error {
+	query := r.URL.Query()
+
+	types := map[string]*jsonAPIType{typ.name: typ}
+	if err := collectJSONAPITypes(typ, types); err != nil {
+		return err
+	}
+
+	if query.Has("include") {
+		m.include = make(map[string]struct{})
+		for _, path := range splitList(query.Get("include")) {
+			if err := validateIncludePath(typ, path); err != nil {
+				return err
+			}
+			parts := strings.Split(path, ".")
+			for i := range parts {
+				m.include[strings.Join(parts[:i+1], ".")] = struct{}{}
+			}
+		}
+	}
+
+	for key, values := range query {
+		if !strings.HasPrefix(key, "fields[") || !strings.HasSuffix(key, "]") {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(key, "fields["), "]")
+		fieldType, ok := types[name]
+		if !ok {
+			continue
+		}
+
+		if m.fields == nil {
+			m.fields = make(map[string]map[string]struct{})
+		}
+		fields := make(map[string]struct{})
+		for _, field := range splitList(strings.Join(values, ",")) {
+			if !fieldType.hasField(field) {
+				return SourceParameter(NewBadRequestMessagef("%s has no field %q", name, field), key)
+			}
+			fields[field] = struct{}{}
+		}
+		m.fields[name] = fields
+	}
+
+	return nil
+}
+
+// collectJSONAPITypes adds the resource types reachable from typ to types
+func collectJSONAPITypes(typ *jsonAPIType, types map[string]*jsonAPIType) error {
+	for _, rel := range typ.relations {
+		relType, _, err := typ.relation(rel.name)
+		if err != nil {
+			return err
+		}
+		if _, ok := types[relType.name]; ok {
+			continue
+		}
+		types[relType.name] = relType
+		if err := collectJSONAPITypes(relType, types); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateIncludePath checks that every part of a dotted include path is a relationship
+func validateIncludePath(typ *jsonAPIType, path string) error {
+	for _, name := range strings.Split(path, ".") {
+		relType, ok, err := typ.relation(name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return SourceParameter(NewBadRequestMessagef("include path %q is not a relationship of %s", path, typ.name), "include")
+		}
+		typ = relType
+	}
+
+	return nil
+}
+
+// splitList splits a comma separated query parameter value, dropping empty entries
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
+
+// data returns the primary data of the document for v
+func (m *jsonAPIMarshaler) data(v reflect.Value) (any, error) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return jsonAPINull, nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		resources := make([]*JSONAPIResource, 0, v.Len())
+		for i := range v.Len() {
+			elem := indirect(v.Index(i))
+			if !elem.IsValid() {
+				continue
+			}
+			m.markSeen(elem)
+		}
+		for i := range v.Len() {
+			elem := indirect(v.Index(i))
+			if !elem.IsValid() {
+				continue
+			}
+			res, err := m.resource(elem, "")
+			if err != nil {
+				return nil, err
+			}
+			resources = append(resources, res)
+		}
+
+		return resources, nil
+	}
+
+	m.markSeen(v)
+
+	return m.resource(v, "")
+}
+
+// markSeen records a primary resource so it is not also included
+func (m *jsonAPIMarshaler) markSeen(v reflect.Value) {
+	if id, err := identifier(v); err == nil {
+		m.seen[*id] = struct{}{}
+	}
+}
+
+// resource returns the resource object for the struct v, found at the relationship path
+func (m *jsonAPIMarshaler) resource(v reflect.Value, path string) (*JSONAPIResource, error) {
+	typ, err := jsonAPITypeOf(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	fields, sparse := m.fields[typ.name]
+	wanted := func(name string) bool {
+		_, ok := fields[name]
+
+		return !sparse || ok
+	}
+
+	res := &JSONAPIResource{
+		Type: typ.name,
+		ID:   formatID(v.Field(typ.id)),
+	}
+	for _, attr := range typ.attrs {
+		fv := v.Field(attr.index)
+		if !wanted(attr.name) || (attr.omitEmpty && fv.IsZero()) {
+			continue
+		}
+		if res.Attributes == nil {
+			res.Attributes = make(map[string]any)
+		}
+		res.Attributes[attr.name] = fv.Interface()
+	}
+
+	for _, rel := range typ.relations {
+		fv := v.Field(rel.index)
+		relPath := rel.name
+		if path != "" {
+			relPath = path + "." + rel.name
+		}
+
+		// related resources are included even when the relationship is not in the sparse fieldset
+		linkage, err := m.relationship(fv, relPath)
+		if err != nil {
+			return nil, err
+		}
+		if !wanted(rel.name) || (rel.omitEmpty && fv.IsZero()) {
+			continue
+		}
+		if res.Relationships == nil {
+			res.Relationships = make(map[string]*JSONAPIRelationship)
+		}
+		res.Relationships[rel.name] = &JSONAPIRelationship{Data: linkage}
+	}
+
+	return res, nil
+}
+
+// relationship returns the resource linkage of the relationship v, including the related resources when requested
+func (m *jsonAPIMarshaler) relationship(v reflect.Value, path string) (any, error) {
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		ids := make([]*JSONAPIResourceIdentifier, 0, v.Len())
+		for i := range v.Len() {
+			elem := indirect(v.Index(i))
+			if !elem.IsValid() {
+				continue
+			}
+			id, err := m.related(elem, path)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+
+		return ids, nil
+	}
+
+	v = indirect(v)
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	return m.related(v, path)
+}
+
+// related returns the identifier of the related resource v, adding it to the included resources when requested
+func (m *jsonAPIMarshaler) related(v reflect.Value, path string) (*JSONAPIResourceIdentifier, error) {
+	id, err := identifier(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := m.include[path]; m.include != nil && !ok {
+		return id, nil
+	}
+	if _, ok := m.seen[*id]; ok {
+		return id, nil
+	}
+	m.seen[*id] = struct{}{}
+
+	// reserve the position so the resource is included before the resources related to it
+	i := len(m.included)
+	m.included = append(m.included, nil)
+	res, err := m.resource(v, path)
+	if err != nil {
+		return nil, err
+	}
+	m.included[i] = res
+
+	return id, nil
+}
+
+// identifier returns the resource identifier of the struct v
+func identifier(v reflect.Value) (*JSONAPIResourceIdentifier, error) {
+	typ, err := jsonAPITypeOf(v.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	return &JSONAPIResourceIdentifier{Type: typ.name, ID: formatID(v.Field(typ.id))}, nil
+}
+
+// indirect dereferences pointers and interfaces, returning the zero Value for nil
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+
+	return v
+}
+
+// formatID formats a resource ID
+func formatID(v reflect.Value) string {
+	switch v.Kind() { //nolint:exhaustive // other kinds are formatted with fmt
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	}
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return s.String()
+	}
+
+	return fmt.Sprint(v.Interface())
+}
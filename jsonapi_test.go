@@ -0,0 +1,217 @@
+package httpio
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/errors/v5"
+	"github.com/google/go-cmp/cmp"
+)
+
+type jsonAPIPerson struct {
+	ID    string `jsonapi:"primary,people"`
+	Name  string `jsonapi:"attr,name"`
+	Email string `jsonapi:"attr,email,omitempty"`
+}
+
+type jsonAPIComment struct {
+	ID     int            `jsonapi:"primary,comments"`
+	Body   string         `jsonapi:"attr,body"`
+	Author *jsonAPIPerson `jsonapi:"relation,author"`
+}
+
+type jsonAPIArticle struct {
+	ID       int64             `jsonapi:"primary,articles"`
+	Title    string            `jsonapi:"attr,title"`
+	Internal string            // untagged fields are not written
+	Author   *jsonAPIPerson    `jsonapi:"relation,author"`
+	Comments []*jsonAPIComment `jsonapi:"relation,comments"`
+}
+
+func TestEncoder_JSONAPI(t *testing.T) {
+	t.Parallel()
+
+	jane := &jsonAPIPerson{ID: "p1", Name: "Jane", Email: "jane@example.com"}
+	john := &jsonAPIPerson{ID: "p2", Name: "John"}
+	article := &jsonAPIArticle{
+		ID:       1,
+		Title:    "JSON:API paints my bikeshed!",
+		Internal: "secret",
+		Author:   jane,
+		Comments: []*jsonAPIComment{{ID: 5, Body: "First!", Author: john}, {ID: 12, Body: "I like XML better", Author: jane}},
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		v          any
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "compound document",
+			v:          article,
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":{"type":"articles","id":"1","attributes":{"title":"JSON:API paints my bikeshed!"},"relationships":{"author":{"data":{"type":"people","id":"p1"}},"comments":{"data":[{"type":"comments","id":"5"},{"type":"comments","id":"12"}]}}},` +
+				`"included":[{"type":"people","id":"p1","attributes":{"email":"jane@example.com","name":"Jane"}},` +
+				`{"type":"comments","id":"5","attributes":{"body":"First!"},"relationships":{"author":{"data":{"type":"people","id":"p2"}}}},` +
+				`{"type":"people","id":"p2","attributes":{"name":"John"}},` +
+				`{"type":"comments","id":"12","attributes":{"body":"I like XML better"},"relationships":{"author":{"data":{"type":"people","id":"p1"}}}}]}`,
+		},
+		{
+			name:       "include and sparse fieldsets",
+			query:      "include=comments.author&fields[articles]=title,comments&fields[people]=name&fields[comments]=author",
+			v:          article,
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":{"type":"articles","id":"1","attributes":{"title":"JSON:API paints my bikeshed!"},"relationships":{"comments":{"data":[{"type":"comments","id":"5"},{"type":"comments","id":"12"}]}}},` +
+				`"included":[{"type":"comments","id":"5","relationships":{"author":{"data":{"type":"people","id":"p2"}}}},` +
+				`{"type":"people","id":"p2","attributes":{"name":"John"}},` +
+				`{"type":"comments","id":"12","relationships":{"author":{"data":{"type":"people","id":"p1"}}}},` +
+				`{"type":"people","id":"p1","attributes":{"name":"Jane"}}]}`,
+		},
+		{
+			name:       "empty include",
+			query:      "include=",
+			v:          []jsonAPIPerson{*jane, *john},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":[{"type":"people","id":"p1","attributes":{"email":"jane@example.com","name":"Jane"}},{"type":"people","id":"p2","attributes":{"name":"John"}}]}`,
+		},
+		{
+			name:       "null data",
+			v:          (*jsonAPIArticle)(nil),
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":null}`,
+		},
+		{
+			name:       "empty relationship",
+			v:          jsonAPIComment{ID: 7, Body: "anonymous"},
+			wantStatus: http.StatusCreated,
+			wantBody:   `{"data":{"type":"comments","id":"7","attributes":{"body":"anonymous"},"relationships":{"author":{"data":null}}}}`,
+		},
+		{
+			name:       "unknown include",
+			query:      "include=editor",
+			v:          article,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"errors":[{"status":"400","code":"badRequest","title":"Bad Request","detail":"include path \"editor\" is not a relationship of articles","source":{"parameter":"include"}}]}`,
+		},
+		{
+			name:       "unknown field",
+			query:      "fields[people]=name,age",
+			v:          article,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"errors":[{"status":"400","code":"badRequest","title":"Bad Request","detail":"people has no field \"age\"","source":{"parameter":"fields[people]"}}]}`,
+		},
+		{
+			name:       "untagged type",
+			v:          struct{ Name string }{Name: "x"},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"errors":[{"status":"500","code":"internalServerError","title":"Internal Server Error"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			status := http.StatusOK
+			if tt.wantStatus == http.StatusCreated {
+				status = http.StatusCreated
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/articles/1?"+tt.query, http.NoBody)
+			recorder := httptest.NewRecorder()
+			_ = NewEncoder(recorder).JSONAPI(r, status, tt.v)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("Encoder.JSONAPI() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if got := recorder.Header().Get("Content-Type"); got != JSONAPIContentType {
+				t.Errorf("Encoder.JSONAPI() Content-Type = %q, want %q", got, JSONAPIContentType)
+			}
+			if got := strings.TrimSpace(recorder.Body.String()); got != tt.wantBody {
+				t.Errorf("Encoder.JSONAPI() body =\n%s\nwant\n%s", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestEncoder_JSONAPI_meta(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/people", http.NoBody)
+	recorder := httptest.NewRecorder()
+	if err := NewEncoder(recorder).JSONAPI(r, http.StatusOK, []*jsonAPIPerson{}, WithMeta(map[string]int{"total": 0})); err != nil {
+		t.Fatalf("Encoder.JSONAPI() error = %v", err)
+	}
+
+	if got, want := strings.TrimSpace(recorder.Body.String()), `{"data":[],"meta":{"total":0}}`; got != want {
+		t.Errorf("Encoder.JSONAPI() body = %s, want %s", got, want)
+	}
+}
+
+func TestJSONAPIEnvelope_sources(t *testing.T) {
+	t.Parallel()
+
+	var typeErr error = &json.UnmarshalTypeError{Value: "number", Field: "data.attributes.title"}
+
+	tests := []struct {
+		name string
+		opts []EncoderOption
+		err  error
+		want []JSONAPIError
+	}{
+		{
+			name: "pointer",
+			err:  SourcePointer(NewUnprocessableEntityMessage("title is required"), "/data/attributes/title"),
+			want: []JSONAPIError{
+				{Status: "422", Code: "unprocessableEntity", Title: "Unprocessable Entity", Detail: "title is required", Source: &JSONAPIErrorSource{Pointer: "/data/attributes/title"}},
+			},
+		},
+		{
+			name: "pointer inside the cause",
+			err:  errors.Wrap(NewBadRequestMessageWithError(SourcePointer(errors.New("too long"), "/data/attributes/title"), "title is too long"), "decode()"),
+			want: []JSONAPIError{
+				{Status: "400", Code: "badRequest", Title: "Bad Request", Detail: "title is too long", Source: &JSONAPIErrorSource{Pointer: "/data/attributes/title"}},
+			},
+		},
+		{
+			name: "unmarshal type error",
+			err:  NewBadRequestMessageWithError(typeErr, "invalid title"),
+			want: []JSONAPIError{
+				{Status: "400", Code: "badRequest", Title: "Bad Request", Detail: "invalid title", Source: &JSONAPIErrorSource{Pointer: "/data/attributes/title"}},
+			},
+		},
+		{
+			name: "nested messages",
+			opts: []EncoderOption{WithNestedMessages(OutermostMessage)},
+			err: NewUnprocessableEntityMessageWithError(
+				SourceParameter(NewBadRequestMessage("unknown sort field"), "sort"),
+				"invalid request",
+			),
+			want: []JSONAPIError{
+				{Status: "422", Code: "unprocessableEntity", Title: "Unprocessable Entity", Detail: "invalid request"},
+				{Status: "422", Code: "unprocessableEntity", Title: "Unprocessable Entity", Detail: "unknown sort field", Source: &JSONAPIErrorSource{Parameter: "sort"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			_ = NewEncoder(recorder, append(tt.opts, WithEnvelope(JSONAPIEnvelope()))...).ClientMessage(context.Background(), tt.err)
+
+			var got JSONAPIDocument
+			if err := json.NewDecoder(recorder.Body).Decode(&got); err != nil {
+				t.Fatalf("json.Decoder.Decode() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got.Errors); diff != "" {
+				t.Errorf("JSONAPIEnvelope errors mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
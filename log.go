@@ -1,16 +1,81 @@
 package httpio
 
 import (
+	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/cccteam/logger"
+	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/errors/v5"
 )
 
+// LogEntry describes an error returned from a handler wrapped by Log
+type LogEntry struct {
+	// Level is slog.LevelInfo for client errors and slog.LevelError for server errors
+	Level slog.Level
+	// StatusCode is the status code of the response
+	StatusCode int
+	// MessageType is the name of the ClientMessage type, such as "notFound", or empty when the error is not a ClientMessage
+	MessageType string
+	// Messages holds the client messages contained within the error chain
+	Messages []string
+	// Err is the error returned from the handler
+	Err error
+	// Route is the chi route pattern that matched the request
+	Route string
+	// Method is the request method
+	Method string
+	// TraceID is the trace ID of the request, if any
+	TraceID string
+}
+
+// LogSink writes the errors logged by Log
+type LogSink interface {
+	Log(r *http.Request, entry *LogEntry)
+}
+
+// LogOption configures Log
+type LogOption func(c *logConfig)
+
+// WithoutErrorResponse disables writing the error response when the handler returns an error
+// without having written a response, leaving the error to only be logged.
+func WithoutErrorResponse() LogOption {
+	return func(c *logConfig) {
+		c.withoutErrorResponse = true
+	}
+}
+
+// WithLogSink sets the LogSink used by Log. The default writes to the cccteam/logger from the request context.
+func WithLogSink(sink LogSink) LogOption {
+	return func(c *logConfig) {
+		c.sink = sink
+	}
+}
+
+// WithLogPolicy sets the LogPolicy used by Log to choose the level of each error and which errors are logged
+func WithLogPolicy(p LogPolicy) LogOption {
+	return func(c *logConfig) {
+		c.policy = newLogPolicy(p)
+	}
+}
+
+type logConfig struct {
+	withoutErrorResponse bool
+	sink                 LogSink
+	policy               *logPolicy
+	metrics              Metrics
+	responseRedactor     Redactor
+	logRedactor          Redactor
+}
+
 // Log returns a http.HandlerFunc that logs any error coming from handlers.
 // This provides a more ergonomic feel by allowing errors to be returned from handlers
 //
+// When the handler returns an error without having written a response, the error is written
+// with Encoder.ClientMessage so the status code matches the error. Use WithoutErrorResponse to disable this.
+//
 // Example usage:
 //
 //	func Handler() http.HandlerFunc {
@@ -19,31 +84,167 @@ import (
 //			return errors.New("error")
 //		})
 //	}
-func Log(handler func(w http.ResponseWriter, r *http.Request) error) http.HandlerFunc {
+func Log(handler func(w http.ResponseWriter, r *http.Request) error, opts ...LogOption) http.HandlerFunc {
+	cfg := &logConfig{
+		sink:    loggerSink{},
+		metrics: noopMetrics{},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
 	return func(w http.ResponseWriter, r *http.Request) {
-		err := handler(w, r)
+		start := time.Now()
+		ctx, a := withAnnotation(r.Context())
+		r = r.WithContext(ctx)
+
+		sw := newStatusWriter(w)
+		err := handler(sw, r)
+		if err != nil && !cfg.withoutErrorResponse && !sw.written() {
+			if rerr := NewEncoder(sw, WithMetrics(cfg.metrics), WithRedactor(cfg.responseRedactor)).ClientMessage(r.Context(), err); rerr != nil {
+				err = rerr
+			}
+		}
+
+		cfg.metrics.ObserveResponse(newResponseObservation(r, sw, a, err, time.Since(start)))
 		if err == nil {
 			return
 		}
 
-		cerr := &ClientMessage{}
-		if !errors.As(err, &cerr) {
-			logger.FromReq(r).Error(err)
-
+		entry := newLogEntry(r, sw, a, err)
+		recordSpanError(ctx, err, entry.StatusCode)
+		if cfg.logRedactor != nil {
+			entry.redact(cfg.logRedactor)
+		}
+		if cfg.policy != nil && !cfg.policy.apply(entry) {
 			return
 		}
 
-		messages := strings.Join(Messages(err), "', '")
-		if cerr.msgType < internalServerError {
-			logger.FromReq(r).Info(err)
-			if messages != "" {
-				logger.FromReq(r).Infof("messages=['%s']", messages)
-			}
-		} else {
-			logger.FromReq(r).Error(err)
-			if messages != "" {
-				logger.FromReq(r).Errorf("messages=['%s']", messages)
-			}
+		cfg.sink.Log(r, entry)
+	}
+}
+
+// newLogEntry describes the error returned for the request
+func newLogEntry(r *http.Request, sw *statusWriter, a *annotation, err error) *LogEntry {
+	// an error written by Encoder.ClientMessage has already been through the error mappers
+	var c Classification
+	if a.err != nil {
+		c = classify(a.err, false)
+	} else {
+		c = Classify(err)
+	}
+	entry := &LogEntry{
+		Level:       slog.LevelError,
+		StatusCode:  c.StatusCode,
+		MessageType: c.MessageType,
+		Messages:    c.Messages,
+		Err:         err,
+		Method:      r.Method,
+		TraceID:     requestTraceID(r.Context()),
+	}
+	if c.StatusCode < http.StatusInternalServerError {
+		entry.Level = slog.LevelInfo
+	}
+	if sw.written() {
+		entry.StatusCode = sw.status
+	}
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		entry.Route = rctx.RoutePattern()
+	}
+
+	return entry
+}
+
+// newResponseObservation describes the response for Metrics
+func newResponseObservation(r *http.Request, sw *statusWriter, a *annotation, err error, duration time.Duration) ResponseObservation {
+	o := ResponseObservation{
+		Method:     r.Method,
+		StatusCode: sw.status,
+		Duration:   duration,
+	}
+	if !sw.written() {
+		o.StatusCode = http.StatusOK
+	}
+	if a.msgType != nil {
+		o.MessageType = a.msgType.String()
+	} else if cerr := (&ClientMessage{}); errors.As(err, &cerr) {
+		o.MessageType = cerr.msgType.String()
+	}
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		o.Route = rctx.RoutePattern()
+	}
+
+	return o
+}
+
+// loggerSink is the default LogSink, which writes to the cccteam/logger from the request context
+type loggerSink struct{}
+
+func (loggerSink) Log(r *http.Request, entry *LogEntry) {
+	l := logger.FromReq(r)
+	messages := strings.Join(entry.Messages, "', '")
+	switch {
+	case entry.Level < slog.LevelInfo:
+		l.Debug(entry.Err)
+		if messages != "" {
+			l.Debugf("messages=['%s']", messages)
+		}
+	case entry.Level < slog.LevelWarn:
+		l.Info(entry.Err)
+		if messages != "" {
+			l.Infof("messages=['%s']", messages)
+		}
+	case entry.Level < slog.LevelError:
+		l.Warn(entry.Err)
+		if messages != "" {
+			l.Warnf("messages=['%s']", messages)
 		}
+	default:
+		l.Error(entry.Err)
+		if messages != "" {
+			l.Errorf("messages=['%s']", messages)
+		}
+	}
+}
+
+// NewSlogSink returns a LogSink that writes structured records to l, or to slog.Default() when l is nil.
+// Records carry the status code, message type, client messages, error chain, route, method and trace ID as attributes.
+func NewSlogSink(l *slog.Logger) LogSink {
+	return &slogSink{logger: l}
+}
+
+type slogSink struct {
+	logger *slog.Logger
+}
+
+func (s *slogSink) Log(r *http.Request, entry *LogEntry) {
+	l := s.logger
+	if l == nil {
+		l = slog.Default()
 	}
+
+	attrs := []slog.Attr{
+		slog.Int("status", entry.StatusCode),
+		slog.String("method", entry.Method),
+	}
+	if entry.Route != "" {
+		attrs = append(attrs, slog.String("route", entry.Route))
+	}
+	if entry.MessageType != "" {
+		attrs = append(attrs, slog.String("messageType", entry.MessageType))
+	}
+	if len(entry.Messages) > 0 {
+		attrs = append(attrs, slog.Any("messages", entry.Messages))
+	}
+	if entry.TraceID != "" {
+		attrs = append(attrs, slog.String("traceId", entry.TraceID))
+	}
+	if entry.Err != nil {
+		attrs = append(attrs,
+			slog.String("error", entry.Err.Error()),
+			slog.Any("errorChain", strings.Split(entry.Err.Error(), "\n")),
+		)
+	}
+
+	l.LogAttrs(r.Context(), entry.Level, "handler error", attrs...)
 }
@@ -0,0 +1,315 @@
+package httpio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/errors/v5"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLog(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		handler    func(w http.ResponseWriter, r *http.Request) error
+		opts       []LogOption
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "no error",
+			handler: func(w http.ResponseWriter, _ *http.Request) error {
+				return NewEncoder(w).Ok(map[string]string{"status": "ok"})
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"status":"ok"}`,
+		},
+		{
+			name: "client message not written",
+			handler: func(_ http.ResponseWriter, _ *http.Request) error {
+				return NewNotFoundMessage("item not found")
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"message":"item not found"}`,
+		},
+		{
+			name: "client message without message not written",
+			handler: func(_ http.ResponseWriter, _ *http.Request) error {
+				return NewConflict()
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name: "error not written",
+			handler: func(_ http.ResponseWriter, _ *http.Request) error {
+				return errors.New("database unavailable")
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "error already written",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return NewEncoder(w).BadRequestMessage(r.Context(), "invalid input")
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"message":"invalid input"}`,
+		},
+		{
+			name: "error after partial response",
+			handler: func(w http.ResponseWriter, _ *http.Request) error {
+				w.WriteHeader(http.StatusAccepted)
+
+				return errors.New("stream failed")
+			},
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name: "without error response",
+			handler: func(_ http.ResponseWriter, _ *http.Request) error {
+				return NewNotFoundMessage("item not found")
+			},
+			opts:       []LogOption{WithoutErrorResponse()},
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			recorder := httptest.NewRecorder()
+
+			Log(tt.handler, tt.opts...).ServeHTTP(recorder, r)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("Log() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if got := strings.TrimSpace(recorder.Body.String()); got != tt.wantBody {
+				t.Errorf("Log() body = %s, want %s", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestStatusWriter(t *testing.T) {
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	sw := newStatusWriter(recorder)
+	if sw.written() {
+		t.Fatalf("statusWriter.written() = true before writing")
+	}
+
+	if _, err := sw.Write([]byte("hello")); err != nil {
+		t.Fatalf("statusWriter.Write() error = %v", err)
+	}
+	if sw.status != http.StatusOK {
+		t.Errorf("statusWriter.status = %d, want %d", sw.status, http.StatusOK)
+	}
+	if sw.bytes != 5 {
+		t.Errorf("statusWriter.bytes = %d, want 5", sw.bytes)
+	}
+
+	if got := newStatusWriter(sw); got != sw {
+		t.Errorf("newStatusWriter() did not reuse the existing statusWriter")
+	}
+
+	cw := newCompressWriter(sw, EncodingGzip, newCompressConfig(nil))
+	if got, ok := unwrapWriter[*statusWriter](cw); !ok || got != sw {
+		t.Errorf("unwrapWriter() = %v, %v, want the wrapped statusWriter", got, ok)
+	}
+}
+
+// hijackRecorder is a ResponseRecorder that supports http.Hijacker
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (h *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h.hijacked = true
+
+	return nil, nil, nil
+}
+
+func TestLog_hijack(t *testing.T) {
+	t.Parallel()
+
+	recorder := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+	handler := Log(func(w http.ResponseWriter, _ *http.Request) error {
+		hj, ok := w.(http.Hijacker)
+		if !ok {
+			t.Fatalf("Log() ResponseWriter does not implement http.Hijacker")
+		}
+		if _, _, err := hj.Hijack(); err != nil {
+			t.Fatalf("http.Hijacker.Hijack() error = %v", err)
+		}
+
+		return nil
+	})
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	if !recorder.hijacked {
+		t.Errorf("Log() did not pass Hijack to the underlying ResponseWriter")
+	}
+}
+
+func TestStatusWriter_hijackNotSupported(t *testing.T) {
+	t.Parallel()
+
+	sw := newStatusWriter(httptest.NewRecorder())
+	if _, _, err := sw.Hijack(); !errors.Is(err, http.ErrNotSupported) {
+		t.Errorf("statusWriter.Hijack() error = %v, want %v", err, http.ErrNotSupported)
+	}
+	if sw.written() {
+		t.Errorf("statusWriter.written() = true after a failed Hijack")
+	}
+}
+
+func TestStatusWriter_readFrom(t *testing.T) {
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	sw := newStatusWriter(recorder)
+	n, err := io.Copy(sw, strings.NewReader("hello"))
+	if err != nil {
+		t.Fatalf("io.Copy() error = %v", err)
+	}
+	if n != 5 || sw.bytes != 5 {
+		t.Errorf("io.Copy() = %d, statusWriter.bytes = %d, want 5", n, sw.bytes)
+	}
+	if sw.status != http.StatusOK {
+		t.Errorf("statusWriter.status = %d, want %d", sw.status, http.StatusOK)
+	}
+	if got := recorder.Body.String(); got != "hello" {
+		t.Errorf("body = %q, want %q", got, "hello")
+	}
+}
+
+type captureSink struct {
+	entries []*LogEntry
+}
+
+func (s *captureSink) Log(_ *http.Request, entry *LogEntry) {
+	s.entries = append(s.entries, entry)
+}
+
+func TestLog_sink(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		err             error
+		wantLevel       slog.Level
+		wantStatus      int
+		wantMessageType string
+		wantMessages    []string
+	}{
+		{
+			name:            "client message",
+			err:             NewNotFoundMessageWithError(NewBadRequestMessage("inner"), "item not found"),
+			wantLevel:       slog.LevelInfo,
+			wantStatus:      http.StatusNotFound,
+			wantMessageType: "notFound",
+			wantMessages:    []string{"item not found", "inner"},
+		},
+		{
+			name:            "server client message",
+			err:             NewServiceUnavailableMessage("try again later"),
+			wantLevel:       slog.LevelError,
+			wantStatus:      http.StatusServiceUnavailable,
+			wantMessageType: "serviceUnavailable",
+			wantMessages:    []string{"try again later"},
+		},
+		{
+			name:       "error",
+			err:        errors.New("database unavailable"),
+			wantLevel:  slog.LevelError,
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sink := &captureSink{}
+			router := chi.NewRouter()
+			router.Get("/items/{id}", Log(func(_ http.ResponseWriter, _ *http.Request) error {
+				return tt.err
+			}, WithLogSink(sink)))
+
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/1", http.NoBody))
+
+			if len(sink.entries) != 1 {
+				t.Fatalf("Log() logged %d entries, want 1", len(sink.entries))
+			}
+			entry := sink.entries[0]
+			if entry.Level != tt.wantLevel {
+				t.Errorf("LogEntry.Level = %v, want %v", entry.Level, tt.wantLevel)
+			}
+			if entry.StatusCode != tt.wantStatus {
+				t.Errorf("LogEntry.StatusCode = %d, want %d", entry.StatusCode, tt.wantStatus)
+			}
+			if entry.MessageType != tt.wantMessageType {
+				t.Errorf("LogEntry.MessageType = %q, want %q", entry.MessageType, tt.wantMessageType)
+			}
+			if diff := cmp.Diff(tt.wantMessages, entry.Messages); diff != "" {
+				t.Errorf("LogEntry.Messages mismatch (-want +got):\n%s", diff)
+			}
+			if entry.Route != "/items/{id}" {
+				t.Errorf("LogEntry.Route = %q, want %q", entry.Route, "/items/{id}")
+			}
+			if entry.Method != http.MethodGet {
+				t.Errorf("LogEntry.Method = %q, want %q", entry.Method, http.MethodGet)
+			}
+			if !errors.Is(entry.Err, tt.err) {
+				t.Errorf("LogEntry.Err = %v, want %v", entry.Err, tt.err)
+			}
+		})
+	}
+}
+
+func TestNewSlogSink(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	sink := NewSlogSink(slog.New(slog.NewJSONHandler(buf, nil)))
+
+	r := httptest.NewRequest(http.MethodPost, "/items", http.NoBody)
+	Log(func(_ http.ResponseWriter, _ *http.Request) error {
+		return NewConflictMessage("item already exists")
+	}, WithLogSink(sink)).ServeHTTP(httptest.NewRecorder(), r)
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	want := map[string]any{
+		"level":       "INFO",
+		"msg":         "handler error",
+		"status":      float64(http.StatusConflict),
+		"method":      http.MethodPost,
+		"messageType": "conflict",
+		"messages":    []any{"item already exists"},
+	}
+	for k, v := range want {
+		if diff := cmp.Diff(v, got[k]); diff != "" {
+			t.Errorf("NewSlogSink() attribute %s mismatch (-want +got):\n%s", k, diff)
+		}
+	}
+	if chain, ok := got["errorChain"].([]any); !ok || len(chain) < 2 {
+		t.Errorf("NewSlogSink() errorChain = %v, want the links of the error chain", got["errorChain"])
+	}
+}
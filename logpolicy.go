@@ -0,0 +1,99 @@
+package httpio
+
+import (
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultSampleInterval is the sampling window used when LogPolicy.SampleInterval is not set
+const defaultSampleInterval = time.Minute
+
+// LogPolicy controls the level of the errors logged by Log and which of them are logged.
+//
+// Example usage:
+//
+//	policy := httpio.LogPolicy{
+//		Levels: map[int]slog.Level{
+//			http.StatusNotFound:     slog.LevelDebug,
+//			http.StatusUnauthorized: slog.LevelWarn,
+//			http.StatusForbidden:    slog.LevelWarn,
+//		},
+//		Suppress:    []int{499},
+//		SampleLimit: 10,
+//	}
+//
+//	httpio.Log(handler, httpio.WithLogPolicy(policy))
+type LogPolicy struct {
+	// Levels overrides the level for a status code. By default, client errors (4xx) are logged
+	// at slog.LevelInfo and server errors (5xx) at slog.LevelError.
+	Levels map[int]slog.Level
+	// Suppress holds the status codes that are never logged
+	Suppress []int
+	// SampleLimit is the number of identical client errors logged in each SampleInterval, where identical
+	// errors have the same status code, method, route and client messages. Server errors are never sampled.
+	// Zero disables sampling.
+	SampleLimit int
+	// SampleInterval is the length of the sampling window. The default is one minute.
+	SampleInterval time.Duration
+}
+
+// logPolicy holds a LogPolicy and the state of its sampling window
+type logPolicy struct {
+	LogPolicy
+	now func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[string]int
+}
+
+func newLogPolicy(p LogPolicy) *logPolicy {
+	if p.SampleInterval <= 0 {
+		p.SampleInterval = defaultSampleInterval
+	}
+
+	return &logPolicy{
+		LogPolicy: p,
+		now:       time.Now,
+		counts:    make(map[string]int),
+	}
+}
+
+// apply sets the level of the entry and reports whether it should be logged
+func (p *logPolicy) apply(entry *LogEntry) bool {
+	if slices.Contains(p.Suppress, entry.StatusCode) {
+		return false
+	}
+
+	if level, ok := p.Levels[entry.StatusCode]; ok {
+		entry.Level = level
+	}
+
+	if p.SampleLimit <= 0 || entry.StatusCode < 400 || entry.StatusCode >= 500 {
+		return true
+	}
+
+	return p.sample(entry)
+}
+
+// sample reports whether the entry is within the sample limit for the current window
+func (p *logPolicy) sample(entry *LogEntry) bool {
+	key := strconv.Itoa(entry.StatusCode) + " " + entry.Method + " " + entry.Route + " " + strings.Join(entry.Messages, "\x00")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// The window is reset as a whole so that the counts cannot grow without bound
+	if now := p.now(); now.Sub(p.windowStart) >= p.SampleInterval {
+		p.windowStart = now
+		clear(p.counts)
+	}
+
+	p.counts[key]++
+
+	return p.counts[key] <= p.SampleLimit
+}
@@ -0,0 +1,105 @@
+package httpio
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLogPolicy_apply(t *testing.T) {
+	t.Parallel()
+
+	policy := LogPolicy{
+		Levels: map[int]slog.Level{
+			http.StatusNotFound:     slog.LevelDebug,
+			http.StatusUnauthorized: slog.LevelWarn,
+		},
+		Suppress: []int{statusClientClosedRequest},
+	}
+
+	tests := []struct {
+		name       string
+		status     int
+		level      slog.Level
+		wantLevel  slog.Level
+		wantLogged bool
+	}{
+		{name: "debug override", status: http.StatusNotFound, level: slog.LevelInfo, wantLevel: slog.LevelDebug, wantLogged: true},
+		{name: "warn override", status: http.StatusUnauthorized, level: slog.LevelInfo, wantLevel: slog.LevelWarn, wantLogged: true},
+		{name: "default", status: http.StatusInternalServerError, level: slog.LevelError, wantLevel: slog.LevelError, wantLogged: true},
+		{name: "suppressed", status: statusClientClosedRequest, level: slog.LevelInfo, wantLevel: slog.LevelInfo, wantLogged: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			entry := &LogEntry{StatusCode: tt.status, Level: tt.level}
+			if got := newLogPolicy(policy).apply(entry); got != tt.wantLogged {
+				t.Errorf("logPolicy.apply() = %v, want %v", got, tt.wantLogged)
+			}
+			if entry.Level != tt.wantLevel {
+				t.Errorf("logPolicy.apply() level = %v, want %v", entry.Level, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestLogPolicy_sample(t *testing.T) {
+	t.Parallel()
+
+	p := newLogPolicy(LogPolicy{SampleLimit: 2, SampleInterval: time.Minute})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	p.now = func() time.Time { return now }
+
+	notFound := func() *LogEntry {
+		return &LogEntry{StatusCode: http.StatusNotFound, Method: http.MethodGet, Route: "/items/{id}", Messages: []string{"item not found"}}
+	}
+
+	var logged int
+	for range 5 {
+		if p.apply(notFound()) {
+			logged++
+		}
+	}
+	if logged != 2 {
+		t.Errorf("logPolicy.apply() logged %d identical errors, want 2", logged)
+	}
+
+	other := notFound()
+	other.Messages = []string{"other"}
+	if !p.apply(other) {
+		t.Errorf("logPolicy.apply() = false for a different error, want true")
+	}
+
+	for range 5 {
+		if !p.apply(&LogEntry{StatusCode: http.StatusInternalServerError}) {
+			t.Fatalf("logPolicy.apply() = false for a server error, want true")
+		}
+	}
+
+	now = now.Add(time.Minute)
+	if !p.apply(notFound()) {
+		t.Errorf("logPolicy.apply() = false in a new window, want true")
+	}
+}
+
+func TestLog_policy(t *testing.T) {
+	t.Parallel()
+
+	sink := &captureSink{}
+	handler := Log(func(_ http.ResponseWriter, _ *http.Request) error {
+		return NewClientClosedRequest()
+	}, WithLogSink(sink), WithLogPolicy(LogPolicy{Suppress: []int{statusClientClosedRequest}}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	if recorder.Code != statusClientClosedRequest {
+		t.Errorf("Log() status = %d, want %d", recorder.Code, statusClientClosedRequest)
+	}
+	if len(sink.entries) != 0 {
+		t.Errorf("Log() logged %d entries, want 0", len(sink.entries))
+	}
+}
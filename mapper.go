@@ -0,0 +1,103 @@
+package httpio
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/go-playground/errors/v5"
+)
+
+// ErrorMapper translates an error into a ClientMessage chain. It returns false when it does not handle the error.
+type ErrorMapper func(err error) (errors.Chain, bool)
+
+// errorMappers holds the mappers registered with RegisterErrorMapper
+var errorMappers struct { //nolint:gochecknoglobals // mappers are registered once for the whole process
+	sync.RWMutex
+	mappers []*ErrorMapper
+}
+
+// RegisterErrorMapper registers a mapper that Encoder.ClientMessage consults for errors that do not
+// contain a ClientMessage. Mappers are consulted in the order they were registered, before the built-in
+// mappers, and the first to handle the error is used. Mappers should wrap the error they are given so
+// that it remains in the chain. The returned function unregisters the mapper.
+//
+// The built-in mappers translate:
+//   - context.DeadlineExceeded to GatewayTimeout (504)
+//   - context.Canceled to ClientClosedRequest (499)
+//   - sql.ErrNoRows to NotFound (404)
+//   - *json.SyntaxError to BadRequest (400)
+//   - *http.MaxBytesError to RequestEntityTooLarge (413)
+//
+// Example usage:
+//
+//	func init() {
+//		httpio.RegisterErrorMapper(func(err error) (errors.Chain, bool) {
+//			if errors.Is(err, store.ErrLocked) {
+//				return httpio.NewConflictMessageWithError(err, "resource is locked"), true
+//			}
+//
+//			return nil, false
+//		})
+//	}
+func RegisterErrorMapper(mapper ErrorMapper) (unregister func()) {
+	registered := &mapper
+
+	errorMappers.Lock()
+	defer errorMappers.Unlock()
+
+	errorMappers.mappers = append(errorMappers.mappers, registered)
+
+	return func() {
+		errorMappers.Lock()
+		defer errorMappers.Unlock()
+
+		// mapError iterates over a copy of the slice header, so the mappers are not removed in place
+		errorMappers.mappers = slices.DeleteFunc(slices.Clone(errorMappers.mappers), func(m *ErrorMapper) bool {
+			return m == registered
+		})
+	}
+}
+
+// mapError translates an error that does not contain a ClientMessage using the registered and built-in mappers
+func mapError(err error) (errors.Chain, bool) {
+	if err == nil || HasClientMessage(err) {
+		return nil, false
+	}
+
+	errorMappers.RLock()
+	mappers := errorMappers.mappers
+	errorMappers.RUnlock()
+
+	for _, mapper := range mappers {
+		if chain, ok := (*mapper)(err); ok {
+			return chain, true
+		}
+	}
+
+	return builtinErrorMapper(err)
+}
+
+// builtinErrorMapper translates common standard library errors
+func builtinErrorMapper(err error) (errors.Chain, bool) {
+	var syntaxErr *json.SyntaxError
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return NewGatewayTimeoutWithError(err), true
+	case errors.Is(err, context.Canceled):
+		return NewClientClosedRequestWithError(err), true
+	case errors.Is(err, sql.ErrNoRows):
+		return NewNotFoundWithError(err), true
+	case errors.As(err, &syntaxErr):
+		return NewBadRequestMessageWithErrorf(err, "request body contains malformed JSON at byte offset %d", syntaxErr.Offset), true
+	case errors.As(err, &maxBytesErr):
+		return NewRequestEntityTooLargeMessageWithErrorf(err, "request body must not be larger than %d bytes", maxBytesErr.Limit), true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,120 @@
+package httpio
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/errors/v5"
+)
+
+// errLocked is only used by the TestRegisterErrorMapper tests so the registered mappers do not affect other tests
+var errLocked = errors.New("resource is locked") //nolint:gochecknoglobals // sentinel error for tests
+
+func TestEncoder_ClientMessage_mappedErrors(t *testing.T) {
+	t.Parallel()
+
+	syntaxErr := json.Unmarshal([]byte(`{"a":}`), &struct{}{})
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "deadline exceeded", err: errors.Wrap(context.DeadlineExceeded, "db.Query()"), wantStatus: http.StatusGatewayTimeout},
+		{name: "canceled", err: context.Canceled, wantStatus: 499},
+		{name: "no rows", err: errors.Wrap(sql.ErrNoRows, "row.Scan()"), wantStatus: http.StatusNotFound},
+		{
+			name:       "json syntax",
+			err:        errors.Wrap(syntaxErr, "json.Unmarshal()"),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"message":"request body contains malformed JSON at byte offset 6"}`,
+		},
+		{
+			name:       "max bytes",
+			err:        &http.MaxBytesError{Limit: 10},
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantBody:   `{"message":"request body must not be larger than 10 bytes"}`,
+		},
+		{name: "client message takes precedence", err: NewConflictWithError(context.Canceled), wantStatus: http.StatusConflict},
+		{name: "unmapped", err: errors.New("boom"), wantStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			err := NewEncoder(recorder).ClientMessage(context.Background(), tt.err)
+			if err == nil {
+				t.Fatalf("Encoder.ClientMessage() error = nil, want error")
+			}
+			if !errors.Is(err, errors.Cause(tt.err)) {
+				t.Errorf("Encoder.ClientMessage() error = %v, want it to wrap %v", err, tt.err)
+			}
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("Encoder.ClientMessage() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if got := strings.TrimSpace(recorder.Body.String()); got != tt.wantBody {
+				t.Errorf("Encoder.ClientMessage() body = %s, want %s", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestRegisterErrorMapper(t *testing.T) { //nolint:paralleltest // registers a mapper for the whole package
+	unregister := RegisterErrorMapper(func(err error) (errors.Chain, bool) {
+		if errors.Is(err, errLocked) {
+			return NewConflictMessageWithError(err, "resource is locked"), true
+		}
+
+		return nil, false
+	})
+
+	recorder := httptest.NewRecorder()
+	err := NewEncoder(recorder).ClientMessage(context.Background(), errors.Wrap(errLocked, "store.Update()"))
+
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("Encoder.ClientMessage() status = %d, want %d", recorder.Code, http.StatusConflict)
+	}
+	if !HasConflict(err) {
+		t.Errorf("Encoder.ClientMessage() error = %v, want Conflict", err)
+	}
+
+	unregister()
+
+	recorder = httptest.NewRecorder()
+	_ = NewEncoder(recorder).ClientMessage(context.Background(), errors.Wrap(errLocked, "store.Update()"))
+
+	if recorder.Code != http.StatusInternalServerError {
+		t.Errorf("Encoder.ClientMessage() status after unregister = %d, want %d", recorder.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestRegisterErrorMapper_log(t *testing.T) { //nolint:paralleltest // registers a mapper for the whole package
+	var calls int
+	defer RegisterErrorMapper(func(err error) (errors.Chain, bool) {
+		if errors.Is(err, errLocked) {
+			calls++
+		}
+
+		return nil, false
+	})()
+
+	handler := Log(func(_ http.ResponseWriter, _ *http.Request) error {
+		return errors.Wrap(errLocked, "store.Update()")
+	}, WithLogSink(&captureSink{}))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	if recorder.Code != http.StatusInternalServerError {
+		t.Errorf("Log() status = %d, want %d", recorder.Code, http.StatusInternalServerError)
+	}
+	if calls != 1 {
+		t.Errorf("Log() called the mapper %d times, want 1", calls)
+	}
+}
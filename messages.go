@@ -0,0 +1,54 @@
+package httpio
+
+// PrimaryMessage chooses which of the nested client messages is sent as the message of a response
+type PrimaryMessage int
+
+const (
+	// OutermostMessage sends the first non-empty client message in the chain
+	OutermostMessage PrimaryMessage = iota
+	// InnermostMessage sends the last non-empty client message in the chain, which is usually the most specific
+	InnermostMessage
+)
+
+// WithNestedMessages sends every non-empty client message in the error chain as the messages of error
+// responses, deduplicated and ordered from outermost to innermost. The message of the response is the
+// one chosen by primary.
+//
+// Example usage:
+//
+//	// responds with {"message":"sku ABC-1 is discontinued","messages":["order rejected","sku ABC-1 is discontinued"]}
+//	e := httpio.NewEncoder(w, httpio.WithNestedMessages(httpio.InnermostMessage))
+//	return e.ClientMessage(ctx, httpio.NewConflictMessageWithError(err, "order rejected"))
+func WithNestedMessages(primary PrimaryMessage) EncoderOption {
+	return func(e *Encoder) {
+		e.nestedMessages = true
+		e.primaryMessage = primary
+	}
+}
+
+// nestedMessages returns the non-empty client messages in the chain of err without duplicates
+func nestedMessages(err error) []string {
+	var messages []string
+	seen := make(map[string]struct{})
+	for _, msg := range Messages(err) {
+		if _, ok := seen[msg]; ok || msg == "" {
+			continue
+		}
+		seen[msg] = struct{}{}
+		messages = append(messages, msg)
+	}
+
+	return messages
+}
+
+// choose returns the primary message of messages
+func (p PrimaryMessage) choose(messages []string) string {
+	if len(messages) == 0 {
+		return ""
+	}
+	if p == InnermostMessage {
+		return messages[len(messages)-1]
+	}
+
+	return messages[0]
+}
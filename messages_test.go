@@ -0,0 +1,87 @@
+package httpio
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/errors/v5"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEncoder_WithNestedMessages(t *testing.T) {
+	t.Parallel()
+
+	nested := NewConflictMessageWithError(
+		errors.Wrap(NewUnprocessableEntityMessageWithError(NewConflictMessage("sku ABC-1 is discontinued"), "order rejected"), "orders.Create()"),
+		"order rejected",
+	)
+
+	tests := []struct {
+		name    string
+		opts    []EncoderOption
+		err     error
+		want    MessageResponse
+		wantErr bool
+	}{
+		{
+			name:    "disabled",
+			err:     nested,
+			want:    MessageResponse{Message: "order rejected"},
+			wantErr: true,
+		},
+		{
+			name:    "outermost",
+			opts:    []EncoderOption{WithNestedMessages(OutermostMessage)},
+			err:     nested,
+			want:    MessageResponse{Message: "order rejected", Messages: []string{"order rejected", "sku ABC-1 is discontinued"}},
+			wantErr: true,
+		},
+		{
+			name:    "innermost",
+			opts:    []EncoderOption{WithNestedMessages(InnermostMessage)},
+			err:     nested,
+			want:    MessageResponse{Message: "sku ABC-1 is discontinued", Messages: []string{"order rejected", "sku ABC-1 is discontinued"}},
+			wantErr: true,
+		},
+		{
+			name: "empty outer message",
+			opts: []EncoderOption{WithNestedMessages(OutermostMessage)},
+			err:  NewBadRequestWithError(NewNotFoundMessage("item not found")),
+			want: MessageResponse{Message: "item not found", Messages: []string{"item not found"}},
+		},
+		{
+			name:    "redacted",
+			opts:    []EncoderOption{WithNestedMessages(InnermostMessage), WithRedactor(DefaultRedactor())},
+			err:     NewNotFoundMessageWithError(NewNotFoundMessage("no user jane@example.com"), "user not found"),
+			want:    MessageResponse{Message: "no user [REDACTED]", Messages: []string{"user not found", "no user [REDACTED]"}},
+			wantErr: true,
+		},
+		{
+			name: "no messages",
+			opts: []EncoderOption{WithNestedMessages(InnermostMessage)},
+			err:  NewNotFound(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			if err := NewEncoder(recorder, tt.opts...).ClientMessage(context.Background(), tt.err); (err != nil) != tt.wantErr {
+				t.Errorf("Encoder.ClientMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var got MessageResponse
+			if recorder.Body.Len() > 0 {
+				if err := json.NewDecoder(recorder.Body).Decode(&got); err != nil {
+					t.Fatalf("json.Decoder.Decode() error = %v", err)
+				}
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Encoder.ClientMessage() body mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
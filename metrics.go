@@ -0,0 +1,51 @@
+package httpio
+
+import (
+	"time"
+)
+
+// ResponseObservation describes a response observed by Log
+type ResponseObservation struct {
+	// Route is the chi route pattern that matched the request
+	Route string
+	// Method is the request method
+	Method string
+	// StatusCode is the status code of the response
+	StatusCode int
+	// MessageType is the name of the ClientMessage type, such as "unprocessableEntity",
+	// or empty when the response was not a ClientMessage
+	MessageType string
+	// Duration is the time taken to handle the request
+	Duration time.Duration
+}
+
+// Metrics records metrics for the responses handled by Log and the Encoder.
+// A Prometheus implementation is provided by the prommetrics package.
+type Metrics interface {
+	// ObserveResponse records a response handled by Log
+	ObserveResponse(o ResponseObservation)
+	// IncEncodeFailure records a response body that the Encoder failed to encode
+	IncEncodeFailure()
+}
+
+// WithMetrics sets the Metrics used by the Encoder to record encode failures
+func WithMetrics(m Metrics) EncoderOption {
+	return func(e *Encoder) {
+		e.metrics = m
+	}
+}
+
+// WithLogMetrics sets the Metrics used by Log to record every response, including those written
+// by Log when the handler returns an error. The default records nothing.
+func WithLogMetrics(m Metrics) LogOption {
+	return func(c *logConfig) {
+		c.metrics = m
+	}
+}
+
+// noopMetrics is the default Metrics, which records nothing
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveResponse(ResponseObservation) {}
+
+func (noopMetrics) IncEncodeFailure() {}
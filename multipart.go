@@ -0,0 +1,310 @@
+package httpio
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"github.com/go-playground/errors/v5"
+)
+
+const (
+	// defaultMaxFileSize is the default limit on the size of a single uploaded file
+	defaultMaxFileSize = 32 << 20
+	// defaultMaxTotalSize is the default limit on the size of a multipart request body
+	defaultMaxTotalSize = 64 << 20
+	// defaultMaxFieldSize is the default limit on the size of a single non-file form field
+	defaultMaxFieldSize = 64 << 10
+	// sniffLen is the number of bytes used to detect the content type of a file
+	sniffLen = 512
+)
+
+// FilePart is a file streamed from a multipart/form-data request.
+// Reading past the configured file size limit returns a RequestEntityTooLarge (413) message.
+type FilePart struct {
+	io.Reader
+	// FieldName is the name of the form field
+	FieldName string
+	// FileName is the file name provided by the client
+	FileName string
+	// ContentType is the media type detected from the file content
+	ContentType string
+	// Header holds the part's MIME headers as sent by the client
+	Header textproto.MIMEHeader
+}
+
+// FileHandler is called for each file in a multipart/form-data request. The file must be
+// consumed before returning, as the next part is read from the same stream.
+type FileHandler func(ctx context.Context, file *FilePart) error
+
+// MultipartOption configures Multipart
+type MultipartOption func(c *multipartConfig)
+
+// MaxFileSize limits the size of each uploaded file. The default is 32 MiB, and zero means no limit.
+func MaxFileSize(n int64) MultipartOption {
+	return func(c *multipartConfig) {
+		c.maxFileSize = n
+	}
+}
+
+// MaxTotalSize limits the size of the whole request body. The default is 64 MiB, and zero means no limit.
+// The server is only told to close the connection, rather than leave the rest of a larger body unread,
+// when the ResponseWriter is passed with MultipartResponseWriter.
+func MaxTotalSize(n int64) MultipartOption {
+	return func(c *multipartConfig) {
+		c.maxTotalSize = n
+	}
+}
+
+// MultipartResponseWriter passes the ResponseWriter of the request to http.MaxBytesReader, so that the
+// server closes the connection after a body larger than MaxTotalSize.
+func MultipartResponseWriter(w http.ResponseWriter) MultipartOption {
+	return func(c *multipartConfig) {
+		c.w = w
+	}
+}
+
+// MaxFieldSize limits the size of each non-file form field. The default is 64 KiB, and zero means no limit.
+func MaxFieldSize(n int64) MultipartOption {
+	return func(c *multipartConfig) {
+		c.maxFieldSize = n
+	}
+}
+
+// AllowedTypes restricts uploaded files to the given media types, which are matched against the type
+// detected from the file content. A type ending in "/*", such as "image/*", matches any subtype.
+// By default all types are allowed.
+func AllowedTypes(mediaTypes ...string) MultipartOption {
+	return func(c *multipartConfig) {
+		c.allowedTypes = mediaTypes
+	}
+}
+
+type multipartConfig struct {
+	// w is told to close the connection when the body is larger than maxTotalSize
+	w            http.ResponseWriter
+	maxFileSize  int64
+	maxTotalSize int64
+	maxFieldSize int64
+	allowedTypes []string
+}
+
+func newMultipartConfig(opts []MultipartOption) *multipartConfig {
+	c := &multipartConfig{
+		maxFileSize:  defaultMaxFileSize,
+		maxTotalSize: defaultMaxTotalSize,
+		maxFieldSize: defaultMaxFieldSize,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// allowed reports whether the media type is permitted
+func (c *multipartConfig) allowed(mediaType string) bool {
+	if len(c.allowedTypes) == 0 {
+		return true
+	}
+
+	for _, t := range c.allowedTypes {
+		if prefix, ok := strings.CutSuffix(t, "/*"); ok {
+			if strings.HasPrefix(mediaType, prefix+"/") {
+				return true
+			}
+
+			continue
+		}
+		if strings.EqualFold(t, mediaType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Multipart streams a multipart/form-data request. Each file is passed to handleFile as it is read,
+// without buffering the whole file, and the remaining form fields are bound to a value of type T
+// using the `form` struct tag and the same conversion rules as Param.
+//
+// All failures are returned as a ClientMessage:
+//   - a request that is not multipart/form-data, or a file with a disallowed type, is an UnsupportedMediaType (415)
+//   - a file, field or request body over its size limit is a RequestEntityTooLarge (413)
+//   - a malformed body or a field that cannot be converted is a BadRequest (400)
+//
+// Errors returned by handleFile are passed through, so a ClientMessage can be used to reject a file.
+//
+// Example usage:
+//
+//	type UploadRequest struct {
+//		Title string `form:"title"`
+//		Tags  []string `form:"tag"`
+//	}
+//
+//	req, err := httpio.Multipart[UploadRequest](r, func(ctx context.Context, f *httpio.FilePart) error {
+//		return store.Save(ctx, f.FileName, f.ContentType, f)
+//	}, httpio.MaxFileSize(10<<20), httpio.AllowedTypes("application/pdf", "image/*"))
+func Multipart[T any](r *http.Request, handleFile FileHandler, opts ...MultipartOption) (T, error) {
+	var v T
+	cfg := newMultipartConfig(opts)
+
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "multipart/form-data" {
+		return v, NewUnsupportedMediaTypeMessage("Content-Type must be multipart/form-data")
+	}
+
+	if cfg.maxTotalSize > 0 {
+		r.Body = http.MaxBytesReader(rootWriter(cfg.w), r.Body, cfg.maxTotalSize)
+	}
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return v, NewBadRequestMessageWithError(err, "invalid multipart/form-data request")
+	}
+
+	fields := make(map[string][]string)
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return v, multipartError(err)
+		}
+
+		if part.FileName() == "" {
+			value, err := readField(part, cfg.maxFieldSize)
+			if err != nil {
+				return v, err
+			}
+			fields[part.FormName()] = append(fields[part.FormName()], value)
+
+			continue
+		}
+
+		if err := readFile(r.Context(), part, handleFile, cfg); err != nil {
+			return v, err
+		}
+	}
+
+	if err := bindValues(&v, "form", func(name string) []string { return fields[name] }); err != nil {
+		return v, err
+	}
+
+	return v, nil
+}
+
+// readField reads the value of a non-file form field
+func readField(part *multipart.Part, maxSize int64) (string, error) {
+	if maxSize <= 0 {
+		b, err := io.ReadAll(part)
+		if err != nil {
+			return "", multipartError(err)
+		}
+
+		return string(b), nil
+	}
+
+	b, err := io.ReadAll(io.LimitReader(part, maxSize+1))
+	if err != nil {
+		return "", multipartError(err)
+	}
+	if int64(len(b)) > maxSize {
+		return "", NewRequestEntityTooLargeMessagef("form field %q must not be larger than %d bytes", part.FormName(), maxSize)
+	}
+
+	return string(b), nil
+}
+
+// readFile detects the content type of a file part and passes it to handleFile
+func readFile(ctx context.Context, part *multipart.Part, handleFile FileHandler, cfg *multipartConfig) error {
+	if handleFile == nil {
+		return NewBadRequestMessagef("unexpected file in form field %q", part.FormName())
+	}
+
+	sniff := make([]byte, sniffLen)
+	n, err := io.ReadFull(part, sniff)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return multipartError(err)
+	}
+	sniff = sniff[:n]
+
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(sniff))
+	if !cfg.allowed(contentType) {
+		return NewUnsupportedMediaTypeMessagef("file %q has unsupported type %q", part.FileName(), contentType)
+	}
+
+	file := &FilePart{
+		Reader: &limitedFileReader{
+			r:        io.MultiReader(bytes.NewReader(sniff), part),
+			fileName: part.FileName(),
+			max:      cfg.maxFileSize,
+		},
+		FieldName:   part.FormName(),
+		FileName:    part.FileName(),
+		ContentType: contentType,
+		Header:      part.Header,
+	}
+
+	if err := handleFile(ctx, file); err != nil {
+		if HasClientMessage(err) {
+			return errors.Wrap(err, "handleFile()")
+		}
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return multipartError(err)
+		}
+
+		return errors.Wrap(err, "handleFile()")
+	}
+
+	return nil
+}
+
+// multipartError converts an error reading the request body into a ClientMessage
+func multipartError(err error) error {
+	if HasClientMessage(err) {
+		return err
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return NewRequestEntityTooLargeMessageWithErrorf(err, "request body must not be larger than %d bytes", maxBytesErr.Limit)
+	}
+
+	return NewBadRequestMessageWithError(err, "invalid multipart/form-data request")
+}
+
+// limitedFileReader returns a RequestEntityTooLarge (413) message once more than max bytes are read
+type limitedFileReader struct {
+	r        io.Reader
+	fileName string
+	max      int64
+	read     int64
+}
+
+func (l *limitedFileReader) Read(p []byte) (int, error) {
+	if l.max > 0 && l.read > l.max {
+		return 0, NewRequestEntityTooLargeMessagef("file %q must not be larger than %d bytes", l.fileName, l.max)
+	}
+
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.max > 0 && l.read > l.max {
+		excess := int(l.read - l.max)
+
+		return n - excess, NewRequestEntityTooLargeMessagef("file %q must not be larger than %d bytes", l.fileName, l.max)
+	}
+
+	if err != nil && !errors.Is(err, io.EOF) {
+		return n, multipartError(err)
+	}
+
+	return n, err //nolint:wrapcheck // io.EOF must be returned unwrapped
+}
@@ -0,0 +1,222 @@
+package httpio
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type uploadRequest struct {
+	Title   string   `form:"title"`
+	Count   int      `form:"count"`
+	Tags    []string `form:"tag"`
+	Ignored string
+}
+
+type testFile struct {
+	field, name, content string
+}
+
+func newMultipartRequest(t *testing.T, fields map[string][]string, files []testFile) *http.Request {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	for name, values := range fields {
+		for _, v := range values {
+			if err := mw.WriteField(name, v); err != nil {
+				t.Fatalf("WriteField() error = %v", err)
+			}
+		}
+	}
+	for _, f := range files {
+		fw, err := mw.CreateFormFile(f.field, f.name)
+		if err != nil {
+			t.Fatalf("CreateFormFile() error = %v", err)
+		}
+		if _, err := io.WriteString(fw, f.content); err != nil {
+			t.Fatalf("WriteString() error = %v", err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+
+	return r
+}
+
+func TestMultipart(t *testing.T) {
+	t.Parallel()
+
+	pdf := "%PDF-1.7\n" + strings.Repeat("pdf data ", 20)
+
+	tests := []struct {
+		name        string
+		fields      map[string][]string
+		files       []testFile
+		opts        []MultipartOption
+		noHandler   bool
+		want        uploadRequest
+		wantFiles   map[string]string
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:      "fields and files",
+			fields:    map[string][]string{"title": {"report"}, "count": {"3"}, "tag": {"a", "b"}},
+			files:     []testFile{{field: "doc", name: "report.pdf", content: pdf}},
+			opts:      []MultipartOption{AllowedTypes("application/pdf")},
+			want:      uploadRequest{Title: "report", Count: 3, Tags: []string{"a", "b"}},
+			wantFiles: map[string]string{"report.pdf": "application/pdf"},
+		},
+		{
+			name:      "wildcard type",
+			files:     []testFile{{field: "doc", name: "notes.txt", content: "plain text notes"}},
+			opts:      []MultipartOption{AllowedTypes("text/*")},
+			wantFiles: map[string]string{"notes.txt": "text/plain"},
+		},
+		{
+			name:        "invalid field",
+			fields:      map[string][]string{"count": {"three"}},
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: `param count=three is not a valid int. err: strconv.Atoi: parsing "three": invalid syntax`,
+		},
+		{
+			name:        "disallowed type",
+			files:       []testFile{{field: "doc", name: "report.pdf", content: pdf}},
+			opts:        []MultipartOption{AllowedTypes("image/*")},
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantMessage: `file "report.pdf" has unsupported type "application/pdf"`,
+		},
+		{
+			name:        "file too large",
+			files:       []testFile{{field: "doc", name: "report.pdf", content: pdf}},
+			opts:        []MultipartOption{MaxFileSize(16)},
+			wantStatus:  http.StatusRequestEntityTooLarge,
+			wantMessage: `file "report.pdf" must not be larger than 16 bytes`,
+		},
+		{
+			name:        "field too large",
+			fields:      map[string][]string{"title": {strings.Repeat("a", 20)}},
+			opts:        []MultipartOption{MaxFieldSize(10)},
+			wantStatus:  http.StatusRequestEntityTooLarge,
+			wantMessage: `form field "title" must not be larger than 10 bytes`,
+		},
+		{
+			name:      "field size unlimited",
+			fields:    map[string][]string{"title": {strings.Repeat("a", 20)}},
+			opts:      []MultipartOption{MaxFieldSize(0)},
+			want:      uploadRequest{Title: strings.Repeat("a", 20)},
+			wantFiles: map[string]string{},
+		},
+		{
+			name:        "request too large",
+			files:       []testFile{{field: "doc", name: "report.pdf", content: pdf}},
+			opts:        []MultipartOption{MaxTotalSize(64)},
+			wantStatus:  http.StatusRequestEntityTooLarge,
+			wantMessage: "request body must not be larger than 64 bytes",
+		},
+		{
+			name:        "file without handler",
+			files:       []testFile{{field: "doc", name: "report.pdf", content: pdf}},
+			noHandler:   true,
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: `unexpected file in form field "doc"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := newMultipartRequest(t, tt.fields, tt.files)
+
+			gotFiles := make(map[string]string)
+			handler := func(_ context.Context, f *FilePart) error {
+				if _, err := io.Copy(io.Discard, f); err != nil {
+					return err
+				}
+				gotFiles[f.FileName] = f.ContentType
+
+				return nil
+			}
+			if tt.noHandler {
+				handler = nil
+			}
+
+			got, err := Multipart[uploadRequest](r, handler, tt.opts...)
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("Multipart() error = %v", err)
+				}
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Errorf("Multipart() mismatch (-want +got):\n%s", diff)
+				}
+				if diff := cmp.Diff(tt.wantFiles, gotFiles); diff != "" {
+					t.Errorf("Multipart() files mismatch (-want +got):\n%s", diff)
+				}
+
+				return
+			}
+
+			recorder := httptest.NewRecorder()
+			_ = NewEncoder(recorder).ClientMessage(r.Context(), err)
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("Multipart() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if got := Message(err); got != tt.wantMessage {
+				t.Errorf("Multipart() message = %q, want %q", got, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestMultipart_notMultipart(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	r.Header.Set("Content-Type", "application/json")
+
+	_, err := Multipart[uploadRequest](r, nil)
+	if !HasUnsupportedMediaType(err) {
+		t.Errorf("Multipart() error = %v, want UnsupportedMediaType", err)
+	}
+}
+
+func TestMultipart_multipartResponseWriter(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(Log(func(w http.ResponseWriter, r *http.Request) error {
+		_, err := Multipart[uploadRequest](r, func(_ context.Context, f *FilePart) error {
+			_, err := io.Copy(io.Discard, f)
+
+			return err
+		}, MaxTotalSize(64), MultipartResponseWriter(w))
+
+		return NewEncoder(w).ClientMessage(r.Context(), err)
+	}))
+	defer server.Close()
+
+	r := newMultipartRequest(t, nil, []testFile{{field: "doc", name: "notes.txt", content: strings.Repeat("notes ", 100)}})
+	resp, err := http.Post(server.URL, r.Header.Get("Content-Type"), r.Body)
+	if err != nil {
+		t.Fatalf("http.Post() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("Multipart() status = %d, want %d", resp.StatusCode, http.StatusRequestEntityTooLarge)
+	}
+	if !resp.Close {
+		t.Errorf("Multipart() did not close the connection after a body larger than MaxTotalSize")
+	}
+}
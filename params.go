@@ -45,135 +45,137 @@ func WithParams(next http.Handler) http.Handler {
 
 // Param extracts the Param from the Request Context
 func Param[T any](r *http.Request, param ParamType) (val T) {
-	fetchParam := func(r *http.Request, param ParamType) any {
-		v := chi.URLParam(r, string(param))
-		if v == "" {
-			panic(newParamErrMsg("route parameter (%s) is required", param))
+	v := chi.URLParam(r, string(param))
+	if v == "" {
+		panic(newParamErrMsg("route parameter (%s) is required", param))
+	}
+
+	pv := parseParam(param, v, val)
+	val, ok := pv.(T)
+	if !ok {
+		panic(fmt.Sprintf("implementation error: returned %T instead of %T", pv, val))
+	}
+
+	return val
+}
+
+// parseParam converts the string value v of param into the type of val.
+// Conversion failures panic with a paramErrMsg, which is recovered by WithParams.
+func parseParam(param ParamType, v string, val any) any {
+	switch val.(type) {
+	case string:
+		return v
+	case int:
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			panic(newParamErrMsg("param %s=%s is not a valid %T. err: %s", param, v, val, err))
 		}
-		switch any(val).(type) {
-		case string:
-			return v
-		case int:
+
+		return i
+	case int64:
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			panic(newParamErrMsg("param %s=%s is not a valid %T. err: %s", param, v, val, err))
+		}
+
+		return i
+	case float64:
+		i, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			panic(newParamErrMsg("param %s=%s is not a valid %T. err: %s", param, v, val, err))
+		}
+
+		return i
+	case bool:
+		i, err := strconv.ParseBool(v)
+		if err != nil {
+			panic(newParamErrMsg("param %s=%s is not a valid %T. err: %s", param, v, val, err))
+		}
+
+		return i
+	case uuid.UUID:
+		i, err := uuid.FromString(v)
+		if err != nil {
+			panic(newParamErrMsg("param %s=%s is not a valid %T. err: %s", param, v, val, err))
+		}
+
+		return i
+	case ccc.UUID:
+		i, err := ccc.UUIDFromString(v)
+		if err != nil {
+			panic(newParamErrMsg("param %s=%s is not a valid %T. err: %s", param, v, val, err))
+		}
+
+		return i
+	default:
+		if val2 := resolveInterfaces(param, v, val); val2 != nil {
+			return val2
+		}
+
+		// handle named types
+		rt := reflect.TypeOf(val)
+		switch rt.Kind() {
+		case reflect.String:
+			return reflect.ValueOf(v).Convert(rt).Interface()
+		case reflect.Int:
 			i, err := strconv.Atoi(v)
 			if err != nil {
 				panic(newParamErrMsg("param %s=%s is not a valid %T. err: %s", param, v, val, err))
 			}
 
-			return i
-		case int64:
+			return reflect.ValueOf(i).Convert(rt).Interface()
+		case reflect.Int64:
 			i, err := strconv.ParseInt(v, 10, 64)
 			if err != nil {
 				panic(newParamErrMsg("param %s=%s is not a valid %T. err: %s", param, v, val, err))
 			}
 
-			return i
-		case float64:
+			return reflect.ValueOf(i).Convert(rt).Interface()
+		case reflect.Float64:
 			i, err := strconv.ParseFloat(v, 64)
 			if err != nil {
 				panic(newParamErrMsg("param %s=%s is not a valid %T. err: %s", param, v, val, err))
 			}
 
-			return i
-		case bool:
+			return reflect.ValueOf(i).Convert(rt).Interface()
+		case reflect.Bool:
 			i, err := strconv.ParseBool(v)
 			if err != nil {
 				panic(newParamErrMsg("param %s=%s is not a valid %T. err: %s", param, v, val, err))
 			}
 
-			return i
-		case uuid.UUID:
-			i, err := uuid.FromString(v)
-			if err != nil {
-				panic(newParamErrMsg("param %s=%s is not a valid %T. err: %s", param, v, val, err))
-			}
-
-			return i
-		case ccc.UUID:
-			i, err := ccc.UUIDFromString(v)
-			if err != nil {
-				panic(newParamErrMsg("param %s=%s is not a valid %T. err: %s", param, v, val, err))
-			}
-
-			return i
+			return reflect.ValueOf(i).Convert(rt).Interface()
 		default:
-			if val2 := resolveInterfaces(param, v, val); val2 != nil {
-				return val2
-			}
-
-			// handle named types
-			rt := reflect.TypeOf(val)
-			switch rt.Kind() {
-			case reflect.String:
-				return reflect.ValueOf(v).Convert(rt).Interface()
-			case reflect.Int:
-				i, err := strconv.Atoi(v)
-				if err != nil {
-					panic(newParamErrMsg("param %s=%s is not a valid %T. err: %s", param, v, val, err))
-				}
-
-				return reflect.ValueOf(i).Convert(rt).Interface()
-			case reflect.Int64:
-				i, err := strconv.ParseInt(v, 10, 64)
+			if rt.ConvertibleTo(reflect.TypeOf(uuid.UUID{})) {
+				i, err := uuid.FromString(v)
 				if err != nil {
 					panic(newParamErrMsg("param %s=%s is not a valid %T. err: %s", param, v, val, err))
 				}
 
 				return reflect.ValueOf(i).Convert(rt).Interface()
-			case reflect.Float64:
-				i, err := strconv.ParseFloat(v, 64)
-				if err != nil {
-					panic(newParamErrMsg("param %s=%s is not a valid %T. err: %s", param, v, val, err))
-				}
-
-				return reflect.ValueOf(i).Convert(rt).Interface()
-			case reflect.Bool:
-				i, err := strconv.ParseBool(v)
-				if err != nil {
-					panic(newParamErrMsg("param %s=%s is not a valid %T. err: %s", param, v, val, err))
-				}
-
-				return reflect.ValueOf(i).Convert(rt).Interface()
-			default:
-				if rt.ConvertibleTo(reflect.TypeOf(uuid.UUID{})) {
-					i, err := uuid.FromString(v)
-					if err != nil {
-						panic(newParamErrMsg("param %s=%s is not a valid %T. err: %s", param, v, val, err))
-					}
-
-					return reflect.ValueOf(i).Convert(rt).Interface()
-				}
-
-				panic(fmt.Sprintf("support for %T has not been implemented", val))
 			}
-		}
-	}
 
-	v := fetchParam(r, param)
-	val, ok := v.(T)
-	if !ok {
-		panic(fmt.Sprintf("implementation error: returned %T instead of %T", v, val))
+			panic(fmt.Sprintf("support for %T has not been implemented", val))
+		}
 	}
-
-	return val
 }
 
-func resolveInterfaces[T any](param ParamType, paramVal string, val T) any {
-	var receivedPtr bool
-	var val2 any
-
+func resolveInterfaces(param ParamType, paramVal string, val any) any {
 	// We need a pointer because these interfaces are implemented on pointer receivers
 	t := reflect.TypeOf(val)
-	if t.Kind() == reflect.Pointer {
-		receivedPtr = true
-		// In this case, T is a nil pointer
-		val2 = reflect.New(t.Elem()).Interface().(T)
+	receivedPtr := t.Kind() == reflect.Pointer
+
+	var ptr reflect.Value
+	if receivedPtr {
+		// In this case, val is a nil pointer
+		ptr = reflect.New(t.Elem())
 	} else {
-		val2 = &val
+		ptr = reflect.New(t)
 	}
 
-	switch t := val2.(type) {
+	switch u := ptr.Interface().(type) {
 	case encoding.TextUnmarshaler:
-		if err := t.UnmarshalText([]byte(paramVal)); err != nil {
+		if err := u.UnmarshalText([]byte(paramVal)); err != nil {
 			panic(newParamErrMsg("param %s=%s is not a valid %T. err: %s", param, paramVal, val, err))
 		}
 	default:
@@ -181,8 +183,8 @@ func resolveInterfaces[T any](param ParamType, paramVal string, val T) any {
 	}
 
 	if receivedPtr {
-		return val2
+		return ptr.Interface()
 	}
 
-	return *(val2.(*T))
+	return ptr.Elem().Interface()
 }
@@ -0,0 +1,109 @@
+// Package prommetrics provides a Prometheus implementation of httpio.Metrics.
+package prommetrics
+
+import (
+	"strconv"
+
+	"github.com/cccteam/httpio"
+	"github.com/go-playground/errors/v5"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Option configures Metrics
+type Option func(c *config)
+
+// WithNamespace sets the namespace prefixed to the metric names. The default is "httpio".
+func WithNamespace(namespace string) Option {
+	return func(c *config) {
+		c.namespace = namespace
+	}
+}
+
+// WithBuckets sets the buckets of the response duration histogram, in seconds.
+// The default is prometheus.DefBuckets.
+func WithBuckets(buckets []float64) Option {
+	return func(c *config) {
+		c.buckets = buckets
+	}
+}
+
+type config struct {
+	namespace string
+	buckets   []float64
+}
+
+// Metrics implements httpio.Metrics with Prometheus collectors
+type Metrics struct {
+	responses      *prometheus.CounterVec
+	duration       *prometheus.HistogramVec
+	encodeFailures prometheus.Counter
+}
+
+var _ httpio.Metrics = (*Metrics)(nil)
+
+// New creates the collectors and registers them with reg. Responses are counted and timed
+// with the labels route, method, status and message_type.
+//
+// Example usage:
+//
+//	m, err := prommetrics.New(prometheus.DefaultRegisterer)
+//	if err != nil {
+//		return err
+//	}
+//
+//	r.Get("/items/{id}", httpio.Log(handler, httpio.WithLogMetrics(m)))
+func New(reg prometheus.Registerer, opts ...Option) (*Metrics, error) {
+	c := &config{
+		namespace: "httpio",
+		buckets:   prometheus.DefBuckets,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	labels := []string{"route", "method", "status", "message_type"}
+	m := &Metrics{
+		responses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: c.namespace,
+			Name:      "responses_total",
+			Help:      "Number of responses by route, method, status code and message type.",
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: c.namespace,
+			Name:      "response_duration_seconds",
+			Help:      "Time taken to handle requests by route, method, status code and message type.",
+			Buckets:   c.buckets,
+		}, labels),
+		encodeFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: c.namespace,
+			Name:      "encode_failures_total",
+			Help:      "Number of response bodies that failed to encode.",
+		}),
+	}
+
+	for _, collector := range []prometheus.Collector{m.responses, m.duration, m.encodeFailures} {
+		if err := reg.Register(collector); err != nil {
+			return nil, errors.Wrap(err, "prometheus.Registerer.Register()")
+		}
+	}
+
+	return m, nil
+}
+
+// ObserveResponse records a response handled by httpio.Log
+func (m *Metrics) ObserveResponse(o httpio.ResponseObservation) {
+	labels := prometheus.Labels{
+		"route":        o.Route,
+		"method":       o.Method,
+		"status":       strconv.Itoa(o.StatusCode),
+		"message_type": o.MessageType,
+	}
+
+	m.responses.With(labels).Inc()
+	m.duration.With(labels).Observe(o.Duration.Seconds())
+}
+
+// IncEncodeFailure records a response body that the httpio.Encoder failed to encode
+func (m *Metrics) IncEncodeFailure() {
+	m.encodeFailures.Inc()
+}
@@ -0,0 +1,70 @@
+package prommetrics
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cccteam/httpio"
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+	m, err := New(reg, WithNamespace("test"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	router := chi.NewRouter()
+	router.Get("/items/{id}", httpio.Log(func(w http.ResponseWriter, r *http.Request) error {
+		if chi.URLParam(r, "id") == "0" {
+			return httpio.NewUnprocessableEntityMessage("id must be positive")
+		}
+		if chi.URLParam(r, "id") == "nan" {
+			return httpio.NewEncoder(w, httpio.WithMetrics(m)).Ok(math.NaN())
+		}
+
+		return httpio.NewEncoder(w, httpio.WithMetrics(m)).Ok(map[string]string{"id": chi.URLParam(r, "id")})
+	}, httpio.WithLogMetrics(m)))
+
+	for _, target := range []string{"/items/1", "/items/2", "/items/0", "/items/nan"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, http.NoBody))
+	}
+
+	want := `
+# HELP test_responses_total Number of responses by route, method, status code and message type.
+# TYPE test_responses_total counter
+test_responses_total{message_type="",method="GET",route="/items/{id}",status="200"} 2
+test_responses_total{message_type="",method="GET",route="/items/{id}",status="500"} 1
+test_responses_total{message_type="unprocessableEntity",method="GET",route="/items/{id}",status="422"} 1
+# HELP test_encode_failures_total Number of response bodies that failed to encode.
+# TYPE test_encode_failures_total counter
+test_encode_failures_total 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "test_responses_total", "test_encode_failures_total"); err != nil {
+		t.Errorf("GatherAndCompare() error = %v", err)
+	}
+
+	if got := testutil.CollectAndCount(m.duration); got != 3 {
+		t.Errorf("duration histogram has %d series, want 3", got)
+	}
+}
+
+func TestNew_duplicateRegistration(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+	if _, err := New(reg); err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := New(reg); err == nil {
+		t.Errorf("New() error = nil, want duplicate registration error")
+	}
+}
@@ -0,0 +1,49 @@
+package httpio
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"github.com/cccteam/logger"
+	"github.com/go-playground/errors/v5"
+)
+
+// Recover middleware recovers panics from handlers. The panic and its stack are logged and an
+// InternalServerError (500) is returned, which includes the trace ID. Param parsing panics are
+// returned as a BadRequest (400), the same as WithParams.
+//
+// http.ErrAbortHandler is re-panicked so the server aborts the response as intended. If the response
+// had already been started when the panic occurred, it cannot be replaced by an error, so the response
+// is aborted the same way to avoid sending the client a truncated body that appears complete.
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := newStatusWriter(w)
+
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+
+			if err, ok := rec.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(rec)
+			}
+
+			if m, ok := rec.(paramErrMsg); ok && !sw.written() {
+				_ = NewEncoder(sw).BadRequestMessage(r.Context(), m.Msg())
+
+				return
+			}
+
+			logger.FromReq(r).Errorf("panic: %v\n%s", rec, debug.Stack())
+
+			if sw.written() {
+				panic(http.ErrAbortHandler)
+			}
+
+			_ = NewEncoder(sw).InternalServerError(r.Context())
+		}()
+
+		next.ServeHTTP(sw, r)
+	})
+}
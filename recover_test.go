@@ -0,0 +1,84 @@
+package httpio
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecover(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantStatus int
+		wantBody   string
+		wantPanic  any
+	}{
+		{
+			name:       "no panic",
+			handler:    func(w http.ResponseWriter, _ *http.Request) { _ = NewEncoder(w).Ok(map[string]string{"status": "ok"}) },
+			wantStatus: http.StatusOK,
+			wantBody:   `{"status":"ok"}`,
+		},
+		{
+			name: "panic",
+			handler: func(_ http.ResponseWriter, _ *http.Request) {
+				var m map[string]int
+				m["boom"]++
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "param panic",
+			handler: func(_ http.ResponseWriter, _ *http.Request) {
+				panic(newParamErrMsg("param id=abc is not a valid int"))
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"message":"param id=abc is not a valid int"}`,
+		},
+		{
+			name: "panic after response started",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+				panic("boom")
+			},
+			wantStatus: http.StatusAccepted,
+			wantPanic:  http.ErrAbortHandler,
+		},
+		{
+			name: "abort handler",
+			handler: func(_ http.ResponseWriter, _ *http.Request) {
+				panic(http.ErrAbortHandler)
+			},
+			wantStatus: http.StatusOK,
+			wantPanic:  http.ErrAbortHandler,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			recorder := httptest.NewRecorder()
+
+			func() {
+				defer func() {
+					if rec := recover(); rec != tt.wantPanic {
+						t.Errorf("Recover() panic = %v, want %v", rec, tt.wantPanic)
+					}
+				}()
+				Recover(tt.handler).ServeHTTP(recorder, r)
+			}()
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("Recover() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if got := strings.TrimSpace(recorder.Body.String()); got != tt.wantBody {
+				t.Errorf("Recover() body = %s, want %s", got, tt.wantBody)
+			}
+		})
+	}
+}
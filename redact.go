@@ -0,0 +1,179 @@
+package httpio
+
+import (
+	"regexp"
+)
+
+// redactedText is the replacement used by DefaultRedactor
+const redactedText = "[REDACTED]"
+
+// defaultRedactPatterns match emails, bearer tokens, JWTs and long opaque tokens
+var defaultRedactPatterns = []*regexp.Regexp{ //nolint:gochecknoglobals // patterns are compiled once
+	regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`),
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`),
+	regexp.MustCompile(`\b[A-Za-z0-9_\-]{32,}\b`),
+}
+
+// cardNumberPattern matches 13 to 19 digits, optionally separated by spaces or dashes, which are
+// only redacted when they pass the Luhn check
+var cardNumberPattern = regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`) //nolint:gochecknoglobals // pattern is compiled once
+
+// Redactor scrubs sensitive text from client messages and log output
+type Redactor interface {
+	// Redact returns s with any sensitive text removed
+	Redact(s string) string
+}
+
+// DefaultRedactor returns a Redactor that replaces emails, bearer tokens, JWTs, opaque tokens of
+// 32 or more characters and card numbers with "[REDACTED]"
+func DefaultRedactor() Redactor {
+	return &defaultRedactor{
+		patterns: NewPatternRedactor(redactedText, defaultRedactPatterns...),
+	}
+}
+
+type defaultRedactor struct {
+	patterns Redactor
+}
+
+func (d *defaultRedactor) Redact(s string) string {
+	s = d.patterns.Redact(s)
+
+	return cardNumberPattern.ReplaceAllStringFunc(s, func(match string) string {
+		if !luhnValid(match) {
+			return match
+		}
+
+		return redactedText
+	})
+}
+
+// luhnValid reports whether the digits of s pass the Luhn checksum used by card numbers
+func luhnValid(s string) bool {
+	var sum int
+	var double bool
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] < '0' || s[i] > '9' {
+			continue
+		}
+
+		d := int(s[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+// NewPatternRedactor returns a Redactor that replaces every match of patterns with replacement
+func NewPatternRedactor(replacement string, patterns ...*regexp.Regexp) Redactor {
+	return &patternRedactor{
+		replacement: replacement,
+		patterns:    patterns,
+	}
+}
+
+type patternRedactor struct {
+	replacement string
+	patterns    []*regexp.Regexp
+}
+
+func (p *patternRedactor) Redact(s string) string {
+	for _, pattern := range p.patterns {
+		s = pattern.ReplaceAllLiteralString(s, p.replacement)
+	}
+
+	return s
+}
+
+// NewAllowListRedactor returns a Redactor that only lets through the given messages, replacing any
+// other non-empty message with replacement. An empty replacement sends no message at all.
+//
+// Example usage:
+//
+//	httpio.NewEncoder(w, httpio.WithRedactor(httpio.NewAllowListRedactor("", "item not found", "name is required")))
+func NewAllowListRedactor(replacement string, messages ...string) Redactor {
+	allowed := make(map[string]struct{}, len(messages))
+	for _, msg := range messages {
+		allowed[msg] = struct{}{}
+	}
+
+	return &allowListRedactor{
+		replacement: replacement,
+		allowed:     allowed,
+	}
+}
+
+type allowListRedactor struct {
+	replacement string
+	allowed     map[string]struct{}
+}
+
+func (a *allowListRedactor) Redact(s string) string {
+	if s == "" {
+		return s
+	}
+	if _, ok := a.allowed[s]; ok {
+		return s
+	}
+
+	return a.replacement
+}
+
+// WithRedactor sets the Redactor applied to the client message of error responses before they are written
+//
+// Example usage:
+//
+//	httpio.NewEncoder(w, httpio.WithRedactor(httpio.DefaultRedactor()))
+func WithRedactor(r Redactor) EncoderOption {
+	return func(e *Encoder) {
+		e.redactor = r
+	}
+}
+
+// WithResponseRedactor sets the Redactor applied to the client message of the error responses written by Log
+func WithResponseRedactor(r Redactor) LogOption {
+	return func(c *logConfig) {
+		c.responseRedactor = r
+	}
+}
+
+// WithLogRedactor sets the Redactor applied to the client messages and error text logged by Log.
+// It is separate from the Redactor for responses, so logs can keep detail that clients must not see.
+func WithLogRedactor(r Redactor) LogOption {
+	return func(c *logConfig) {
+		c.logRedactor = r
+	}
+}
+
+// redact applies r to the messages and error of the entry
+func (entry *LogEntry) redact(r Redactor) {
+	messages := make([]string, 0, len(entry.Messages))
+	for _, msg := range entry.Messages {
+		messages = append(messages, r.Redact(msg))
+	}
+	entry.Messages = messages
+	entry.Err = &redactedError{err: entry.Err, redactor: r}
+}
+
+// redactedError redacts the text of an error while keeping it in the chain for errors.Is and errors.As
+type redactedError struct {
+	err      error
+	redactor Redactor
+}
+
+// Error returns the redacted error message
+func (e *redactedError) Error() string {
+	return e.redactor.Redact(e.err.Error())
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
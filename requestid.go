@@ -0,0 +1,124 @@
+package httpio
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/cccteam/logger"
+	"github.com/gofrs/uuid"
+)
+
+// RequestIDHeader is the header that carries the request ID
+const RequestIDHeader = "X-Request-ID"
+
+// traceparentHeader is the W3C Trace Context header
+const traceparentHeader = "traceparent"
+
+// maxRequestIDLength is the longest X-Request-ID accepted from a client
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// RequestID middleware gives every request an ID, which is stored in the request context and echoed in
+// the X-Request-ID response header. The ID is taken from, in order:
+//   - a valid X-Request-ID request header
+//   - the trace ID of a valid W3C traceparent request header
+//   - a newly generated UUIDv7
+//
+// Error responses, Log and AccessLog use the request ID as the trace ID when the logger from the
+// request context has none.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = ""
+			if traceID, _, ok := parseTraceparent(r.Header.Get(traceparentHeader)); ok {
+				id = traceID
+			}
+		}
+		if id == "" {
+			id = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+// RequestIDFromCtx returns the request ID stored by RequestID, or an empty string
+func RequestIDFromCtx(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+
+	return id
+}
+
+// requestTraceID returns the trace ID of the logger from ctx, falling back to the request ID
+func requestTraceID(ctx context.Context) string {
+	if id := logger.FromCtx(ctx).TraceID(); id != "" {
+		return id
+	}
+
+	return RequestIDFromCtx(ctx)
+}
+
+// newRequestID returns a new UUIDv7, or a UUIDv4 if the clock or random source fails
+func newRequestID() string {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.Must(uuid.NewV4()).String()
+	}
+
+	return id.String()
+}
+
+// validRequestID reports whether id is a non-empty printable ASCII value without spaces of a sensible length
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := range len(id) {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseTraceparent returns the trace ID and parent ID of a W3C traceparent header value,
+// formatted as version-traceid-parentid-flags
+func parseTraceparent(v string) (traceID, parentID string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(v), "-")
+	if len(parts) < 4 {
+		return "", "", false
+	}
+	version, traceID, parentID, flags := parts[0], parts[1], parts[2], parts[3]
+
+	// version ff is invalid, and only version 00 has exactly four fields
+	if !isLowerHex(version, 2) || version == "ff" || (version == "00" && len(parts) != 4) {
+		return "", "", false
+	}
+	if !isLowerHex(traceID, 32) || traceID == strings.Repeat("0", 32) {
+		return "", "", false
+	}
+	if !isLowerHex(parentID, 16) || parentID == strings.Repeat("0", 16) {
+		return "", "", false
+	}
+	if !isLowerHex(flags, 2) {
+		return "", "", false
+	}
+
+	return traceID, parentID, true
+}
+
+// isLowerHex reports whether s is n lowercase hex characters
+func isLowerHex(s string, n int) bool {
+	if len(s) != n || strings.ToLower(s) != s {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+
+	return err == nil
+}
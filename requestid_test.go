@@ -0,0 +1,139 @@
+package httpio
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofrs/uuid"
+)
+
+func TestRequestID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		wantID      string
+		wantNewUUID bool
+	}{
+		{name: "generated", wantNewUUID: true},
+		{name: "request header", headers: map[string]string{RequestIDHeader: "req-123"}, wantID: "req-123"},
+		{
+			name: "traceparent",
+			headers: map[string]string{
+				"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			},
+			wantID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name: "request header before traceparent",
+			headers: map[string]string{
+				RequestIDHeader: "req-123",
+				"traceparent":   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			},
+			wantID: "req-123",
+		},
+		{
+			name: "invalid request header",
+			headers: map[string]string{
+				RequestIDHeader: "bad id",
+				"traceparent":   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			},
+			wantID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{name: "invalid traceparent", headers: map[string]string{"traceparent": "00-00000000000000000000000000000000-00f067aa0ba902b7-01"}, wantNewUUID: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var ctxID string
+			handler := RequestID(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				ctxID = RequestIDFromCtx(r.Context())
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, r)
+
+			headerID := recorder.Header().Get(RequestIDHeader)
+			if headerID != ctxID {
+				t.Errorf("RequestID() header = %q, context = %q, want them equal", headerID, ctxID)
+			}
+			if tt.wantNewUUID {
+				id, err := uuid.FromString(ctxID)
+				if err != nil || id.Version() != uuid.V7 {
+					t.Errorf("RequestIDFromCtx() = %q, want a UUIDv7", ctxID)
+				}
+
+				return
+			}
+			if ctxID != tt.wantID {
+				t.Errorf("RequestIDFromCtx() = %q, want %q", ctxID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestParseTraceparent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		v            string
+		wantTraceID  string
+		wantParentID string
+		wantOK       bool
+	}{
+		{name: "valid", v: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736", wantParentID: "00f067aa0ba902b7", wantOK: true},
+		{name: "future version with extra fields", v: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736", wantParentID: "00f067aa0ba902b7", wantOK: true},
+		{name: "version 00 with extra fields", v: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra"},
+		{name: "version ff", v: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{name: "uppercase", v: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"},
+		{name: "zero trace ID", v: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		{name: "zero parent ID", v: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01"},
+		{name: "short trace ID", v: "00-4bf92f3577b34da6-00f067aa0ba902b7-01"},
+		{name: "missing fields", v: "00-4bf92f3577b34da6a3ce929d0e0e4736"},
+		{name: "empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			traceID, parentID, ok := parseTraceparent(tt.v)
+			if traceID != tt.wantTraceID || parentID != tt.wantParentID || ok != tt.wantOK {
+				t.Errorf("parseTraceparent() = %q, %q, %v, want %q, %q, %v", traceID, parentID, ok, tt.wantTraceID, tt.wantParentID, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRequestID_errorResponse(t *testing.T) {
+	t.Parallel()
+
+	handler := RequestID(Log(func(_ http.ResponseWriter, _ *http.Request) error {
+		return NewNotFoundMessage("item not found")
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	r.Header.Set(RequestIDHeader, "req-123")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, r)
+
+	var got MessageResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&got); err != nil {
+		t.Fatalf("json.Decoder.Decode() error = %v", err)
+	}
+	if got.TraceID != "req-123" {
+		t.Errorf("MessageResponse.TraceID = %q, want %q", got.TraceID, "req-123")
+	}
+	if id := requestTraceID(context.Background()); id != "" {
+		t.Errorf("requestTraceID() = %q, want empty without a request ID", id)
+	}
+}
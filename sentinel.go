@@ -0,0 +1,67 @@
+package httpio
+
+// Sentinel errors for each ClientMessage type. They match any ClientMessage of the same type with
+// errors.Is, from either the standard library or go-playground/errors, and can be returned or wrapped
+// with fmt.Errorf to produce a response with that status code:
+//
+//	if errors.Is(err, httpio.ErrNotFound) {
+//		// handle a missing item
+//	}
+//
+//	return fmt.Errorf("item %d: %w", id, httpio.ErrNotFound)
+//
+//nolint:gochecknoglobals // sentinel errors
+var (
+	ErrBadRequest                   error = &ClientMessage{msgType: badRequest, sentinel: true}                   // http code 400
+	ErrUnauthorized                 error = &ClientMessage{msgType: unauthorized, sentinel: true}                 // http code 401
+	ErrForbidden                    error = &ClientMessage{msgType: forbidden, sentinel: true}                    // http code 403
+	ErrNotFound                     error = &ClientMessage{msgType: notFound, sentinel: true}                     // http code 404
+	ErrMethodNotAllowed             error = &ClientMessage{msgType: methodNotAllowed, sentinel: true}             // http code 405
+	ErrNotAcceptable                error = &ClientMessage{msgType: notAcceptable, sentinel: true}                // http code 406
+	ErrRequestTimeout               error = &ClientMessage{msgType: requestTimeout, sentinel: true}               // http code 408
+	ErrConflict                     error = &ClientMessage{msgType: conflict, sentinel: true}                     // http code 409
+	ErrRequestEntityTooLarge        error = &ClientMessage{msgType: requestEntityTooLarge, sentinel: true}        // http code 413
+	ErrUnsupportedMediaType         error = &ClientMessage{msgType: unsupportedMediaType, sentinel: true}         // http code 415
+	ErrRequestedRangeNotSatisfiable error = &ClientMessage{msgType: requestedRangeNotSatisfiable, sentinel: true} // http code 416
+	ErrUnprocessableEntity          error = &ClientMessage{msgType: unprocessableEntity, sentinel: true}          // http code 422
+	ErrTooManyRequests              error = &ClientMessage{msgType: tooManyRequests, sentinel: true}              // http code 429
+	ErrClientClosedRequest          error = &ClientMessage{msgType: clientClosedRequest, sentinel: true}          // http code 499
+	ErrInternalServerError          error = &ClientMessage{msgType: internalServerError, sentinel: true}          // http code 500
+	ErrNotImplemented               error = &ClientMessage{msgType: notImplemented, sentinel: true}               // http code 501
+	ErrBadGateway                   error = &ClientMessage{msgType: badGateway, sentinel: true}                   // http code 502
+	ErrServiceUnavailable           error = &ClientMessage{msgType: serviceUnavailable, sentinel: true}           // http code 503
+	ErrGatewayTimeout               error = &ClientMessage{msgType: gatewayTimeout, sentinel: true}               // http code 504
+)
+
+// Sentinel errors that match any ClientMessage in a status class with errors.Is
+//
+//nolint:gochecknoglobals // sentinel errors
+var (
+	// ErrClientError matches any ClientMessage with a client error (4xx) status code
+	ErrClientError error = &statusClassError{class: 4, name: "client error"}
+	// ErrServerError matches any ClientMessage with a server error (5xx) status code
+	ErrServerError error = &statusClassError{class: 5, name: "server error"}
+)
+
+// statusClassError is a sentinel error for a class of status codes
+type statusClassError struct {
+	class int
+	name  string
+}
+
+func (s *statusClassError) Error() string {
+	return s.name
+}
+
+// Is reports whether the ClientMessage matches target. A ClientMessage matches a sentinel of the same type,
+// such as ErrNotFound, and the sentinel for its status class, ErrClientError or ErrServerError.
+func (c *ClientMessage) Is(target error) bool {
+	switch t := target.(type) {
+	case *ClientMessage:
+		return t.sentinel && t.msgType == c.msgType
+	case *statusClassError:
+		return c.StatusCode()/100 == t.class
+	}
+
+	return false
+}
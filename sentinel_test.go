@@ -0,0 +1,73 @@
+package httpio
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/errors/v5"
+)
+
+func TestClientMessage_Is(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		err       error
+		target    error
+		wantMatch bool
+	}{
+		{name: "same type", err: NewNotFoundMessage("item not found"), target: ErrNotFound, wantMatch: true},
+		{name: "different type", err: NewNotFoundMessage("item not found"), target: ErrConflict, wantMatch: false},
+		{name: "wrapped with fmt", err: fmt.Errorf("lookup: %w", NewForbidden()), target: ErrForbidden, wantMatch: true},
+		{name: "wrapped with chain", err: errors.Wrap(NewTooManyRequestsWithError(stderrors.New("limit")), "limiter"), target: ErrTooManyRequests, wantMatch: true},
+		{name: "nested type", err: NewBadGatewayWithError(NewServiceUnavailable()), target: ErrServiceUnavailable, wantMatch: true},
+		{name: "client error class", err: NewUnprocessableEntity(), target: ErrClientError, wantMatch: true},
+		{name: "client closed request class", err: NewClientClosedRequest(), target: ErrClientError, wantMatch: true},
+		{name: "server error class", err: NewGatewayTimeout(), target: ErrServerError, wantMatch: true},
+		{name: "wrong class", err: NewNotFound(), target: ErrServerError, wantMatch: false},
+		{name: "sentinel itself", err: ErrNotFound, target: ErrNotFound, wantMatch: true},
+		{name: "wrapped sentinel", err: fmt.Errorf("item 7: %w", ErrNotFound), target: ErrClientError, wantMatch: true},
+		{name: "non-sentinel target", err: NewNotFound(), target: NewNotFound(), wantMatch: false},
+		{name: "not a client message", err: stderrors.New("boom"), target: ErrInternalServerError, wantMatch: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := stderrors.Is(tt.err, tt.target); got != tt.wantMatch {
+				t.Errorf("errors.Is() = %v, want %v", got, tt.wantMatch)
+			}
+			if got := errors.Is(tt.err, tt.target); got != tt.wantMatch {
+				t.Errorf("go-playground errors.Is() = %v, want %v", got, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestSentinel(t *testing.T) {
+	t.Parallel()
+
+	err := fmt.Errorf("item 7: %w", ErrNotFound)
+	if got, want := err.Error(), "item 7: Not Found"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !HasNotFound(err) {
+		t.Errorf("HasNotFound() = false, want true")
+	}
+	if got := ErrClientClosedRequest.Error(); got != "Client Closed Request" {
+		t.Errorf("Error() = %q, want %q", got, "Client Closed Request")
+	}
+
+	recorder := httptest.NewRecorder()
+	_ = NewEncoder(recorder).ClientMessage(context.Background(), err)
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("Encoder.ClientMessage() status = %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+	if recorder.Body.Len() != 0 {
+		t.Errorf("Encoder.ClientMessage() body = %q, want empty", recorder.Body.String())
+	}
+}
@@ -0,0 +1,58 @@
+package httpio
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/errors/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span attribute keys set when an error response is recorded
+const (
+	attrStatusCode    = attribute.Key("http.response.status_code")
+	attrMessageType   = attribute.Key("httpio.message_type")
+	attrClientMessage = attribute.Key("httpio.client_message")
+	attrErrorChain    = attribute.Key("httpio.error_chain")
+)
+
+// recordSpanError records an error response on the active span in the context. The span
+// status is only set to Error for server errors (5xx), following the OpenTelemetry HTTP conventions.
+func recordSpanError(ctx context.Context, err error, statusCode int) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	if a, ok := ctx.Value(annotationKey{}).(*annotation); ok {
+		if a.spanRecorded {
+			return
+		}
+		a.spanRecorded = true
+	}
+
+	attrs := []attribute.KeyValue{attrStatusCode.Int(statusCode)}
+	cerr := &ClientMessage{}
+	if errors.As(err, &cerr) {
+		attrs = append(attrs, attrMessageType.String(cerr.msgType.String()))
+		if msg := cerr.Message(); msg != "" {
+			attrs = append(attrs, attrClientMessage.String(msg))
+		}
+	}
+	span.SetAttributes(attrs...)
+
+	if CauseIsError(err) || Message(err) != "" {
+		span.RecordError(err, trace.WithAttributes(attrErrorChain.StringSlice(strings.Split(err.Error(), "\n"))))
+	}
+
+	if statusCode >= http.StatusInternalServerError {
+		description := Message(err)
+		if description == "" {
+			description = http.StatusText(statusCode)
+		}
+		span.SetStatus(codes.Error, description)
+	}
+}
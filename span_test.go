@@ -0,0 +1,130 @@
+package httpio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/errors/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRecordSpanError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		handler      func(w http.ResponseWriter, r *http.Request) error
+		wantAttrs    map[attribute.Key]attribute.Value
+		wantStatus   codes.Code
+		wantEvents   int
+		wantNoAttrOf []attribute.Key
+	}{
+		{
+			name: "client message",
+			handler: func(_ http.ResponseWriter, _ *http.Request) error {
+				return NewNotFoundMessage("item not found")
+			},
+			wantAttrs: map[attribute.Key]attribute.Value{
+				attrStatusCode:    attribute.IntValue(http.StatusNotFound),
+				attrMessageType:   attribute.StringValue("notFound"),
+				attrClientMessage: attribute.StringValue("item not found"),
+			},
+			wantStatus: codes.Unset,
+			wantEvents: 1,
+		},
+		{
+			name: "encoded by handler",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return NewEncoder(w).ServiceUnavailableMessageWithError(r.Context(), errors.New("pool exhausted"), "try again later")
+			},
+			wantAttrs: map[attribute.Key]attribute.Value{
+				attrStatusCode:    attribute.IntValue(http.StatusServiceUnavailable),
+				attrMessageType:   attribute.StringValue("serviceUnavailable"),
+				attrClientMessage: attribute.StringValue("try again later"),
+			},
+			wantStatus: codes.Error,
+			wantEvents: 1,
+		},
+		{
+			name: "error",
+			handler: func(_ http.ResponseWriter, _ *http.Request) error {
+				return errors.New("database unavailable")
+			},
+			wantAttrs: map[attribute.Key]attribute.Value{
+				attrStatusCode: attribute.IntValue(http.StatusInternalServerError),
+			},
+			wantStatus:   codes.Error,
+			wantEvents:   1,
+			wantNoAttrOf: []attribute.Key{attrMessageType, attrClientMessage},
+		},
+		{
+			name: "client message with cause",
+			handler: func(_ http.ResponseWriter, _ *http.Request) error {
+				return NewBadGatewayWithError(errors.New("upstream failed"))
+			},
+			wantAttrs: map[attribute.Key]attribute.Value{
+				attrStatusCode:  attribute.IntValue(http.StatusBadGateway),
+				attrMessageType: attribute.StringValue("badGateway"),
+			},
+			wantStatus: codes.Error,
+			wantEvents: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			ctx, span := tp.Tracer("test").Start(context.Background(), "request")
+			r := httptest.NewRequest(http.MethodGet, "/", http.NoBody).WithContext(ctx)
+			Log(tt.handler).ServeHTTP(httptest.NewRecorder(), r)
+			span.End()
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("recorded %d spans, want 1", len(spans))
+			}
+			got := spans[0]
+
+			attrs := make(map[attribute.Key]attribute.Value)
+			for _, kv := range got.Attributes() {
+				attrs[kv.Key] = kv.Value
+			}
+			for k, want := range tt.wantAttrs {
+				if attrs[k] != want {
+					t.Errorf("span attribute %s = %v, want %v", k, attrs[k].Emit(), want.Emit())
+				}
+			}
+			for _, k := range tt.wantNoAttrOf {
+				if v, ok := attrs[k]; ok {
+					t.Errorf("span attribute %s = %v, want none", k, v.Emit())
+				}
+			}
+			if got.Status().Code != tt.wantStatus {
+				t.Errorf("span status = %v, want %v", got.Status().Code, tt.wantStatus)
+			}
+			if len(got.Events()) != tt.wantEvents {
+				t.Errorf("span recorded %d events, want %d", len(got.Events()), tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestRecordSpanError_notRecording(t *testing.T) {
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	if err := NewEncoder(recorder).NotFoundMessage(context.Background(), "item not found"); err == nil {
+		t.Fatalf("Encoder.NotFoundMessage() error = nil, want error")
+	}
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("Encoder.NotFoundMessage() status = %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+}
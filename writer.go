@@ -0,0 +1,133 @@
+package httpio
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/go-playground/errors/v5"
+)
+
+// statusWriter is a http.ResponseWriter that records whether a response has been started and its status code
+type statusWriter struct {
+	http.ResponseWriter
+	// status holds the status code sent to the client, or zero if the headers have not been written
+	status int
+	// bytes holds the number of body bytes written
+	bytes int64
+}
+
+// newStatusWriter wraps w, reusing w when it is already a statusWriter
+func newStatusWriter(w http.ResponseWriter) *statusWriter {
+	if sw, ok := w.(*statusWriter); ok {
+		return sw
+	}
+
+	return &statusWriter{ResponseWriter: w}
+}
+
+// Unwrap returns the underlying ResponseWriter for use by http.ResponseController
+func (sw *statusWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
+
+// written reports whether the headers have been sent to the client
+func (sw *statusWriter) written() bool {
+	return sw.status != 0
+}
+
+func (sw *statusWriter) WriteHeader(statusCode int) {
+	if !sw.written() && (statusCode < 100 || statusCode > 199 || statusCode == http.StatusSwitchingProtocols) {
+		sw.status = statusCode
+	}
+
+	sw.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (sw *statusWriter) Write(p []byte) (int, error) {
+	if !sw.written() {
+		sw.status = http.StatusOK
+	}
+
+	n, err := sw.ResponseWriter.Write(p)
+	sw.bytes += int64(n)
+	if err != nil {
+		return n, errors.Wrap(err, "http.ResponseWriter.Write()")
+	}
+
+	return n, nil
+}
+
+// Flush sends any buffered data to the client, which also sends the headers
+func (sw *statusWriter) Flush() {
+	if !sw.written() {
+		sw.status = http.StatusOK
+	}
+
+	_ = http.NewResponseController(sw.ResponseWriter).Flush()
+}
+
+// ReadFrom copies r to the underlying ResponseWriter, using its io.ReaderFrom when it has one
+func (sw *statusWriter) ReadFrom(r io.Reader) (int64, error) {
+	if !sw.written() {
+		sw.status = http.StatusOK
+	}
+
+	var n int64
+	var err error
+	if rf, ok := sw.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = rf.ReadFrom(r)
+	} else {
+		n, err = io.Copy(sw.ResponseWriter, r)
+	}
+	sw.bytes += n
+	if err != nil {
+		return n, errors.Wrap(err, "io.ReaderFrom.ReadFrom()")
+	}
+
+	return n, nil
+}
+
+// Hijack takes over the connection of the underlying ResponseWriter. The error wraps http.ErrNotSupported
+// when the underlying ResponseWriter cannot be hijacked.
+func (sw *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(sw.ResponseWriter).Hijack()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "http.ResponseController.Hijack()")
+	}
+	if !sw.written() {
+		sw.status = http.StatusSwitchingProtocols
+	}
+
+	return conn, rw, nil
+}
+
+// rootWriter returns the innermost ResponseWriter in the chain of wrapped writers, which is the one
+// http.MaxBytesReader can tell to close the connection. A nil w returns nil.
+func rootWriter(w http.ResponseWriter) http.ResponseWriter {
+	for {
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return w
+		}
+		w = u.Unwrap()
+	}
+}
+
+// unwrapWriter returns the first ResponseWriter of type T in the chain of wrapped writers
+func unwrapWriter[T http.ResponseWriter](w http.ResponseWriter) (T, bool) {
+	for {
+		if t, ok := w.(T); ok {
+			return t, true
+		}
+
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			var zero T
+
+			return zero, false
+		}
+		w = u.Unwrap()
+	}
+}